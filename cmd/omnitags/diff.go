@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text or json")
+	failOnBreaking := fs.Bool("fail-on-breaking", false, "exit non-zero when a change is breaking")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: omnitags diff [flags] old.json new.json")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var schemas [2]*config.Schema
+	for i, path := range fs.Args() {
+		data, err := config.ReadEnvironmentFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %q: %w", path, err)
+		}
+		schemas[i] = config.ParseSchema(data)
+	}
+	diff := config.DiffSchemas(schemas[0], schemas[1])
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			return err
+		}
+	case "text":
+		for _, change := range diff.Changes {
+			fmt.Printf("%s %s%s\n", changeMarker(change), change, changeImpact(change))
+		}
+		fmt.Printf("%d changes, %d breaking, migration needed: %t, frontend changes: %t\n", len(diff.Changes), diff.Breaking, diff.Migration, diff.Frontend)
+	default:
+		return fmt.Errorf("diff: unknown format %q", *format)
+	}
+
+	if *failOnBreaking && diff.Breaking > 0 {
+		return fmt.Errorf("diff: %d breaking changes", diff.Breaking)
+	}
+	return nil
+}
+
+// changeMarker prefixes each line like a unified diff
+func changeMarker(c config.Change) string {
+	switch c.Kind {
+	case config.ChangeAdded:
+		return "+"
+	case config.ChangeRemoved:
+		return "-"
+	}
+	return "~"
+}
+
+// changeImpact lists the classification of a change in brackets
+func changeImpact(c config.Change) string {
+	var impact string
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{{c.Breaking, "breaking"}, {c.Migration, "migration"}, {c.Frontend, "frontend"}} {
+		if flag.set {
+			impact += ", " + flag.name
+		}
+	}
+	if impact == "" {
+		return ""
+	}
+	return " [" + impact[2:] + "]"
+}
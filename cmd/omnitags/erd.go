@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runERD(args []string) error {
+	fs := flag.NewFlagSet("erd", flag.ExitOnError)
+	src := sourceFlags(fs)
+	format := fs.String("format", "mermaid", "diagram format: "+strings.Join(config.ERDFormats(), ", "))
+	group := fs.String("group", "", "comma-separated table groups to draw, all groups when empty")
+	out := fs.String("out", "", "output file, stdout when empty")
+	fs.Parse(args)
+
+	c, err := config.LoadOmnitags(*src)
+	if err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	var groups []string
+	if *group != "" {
+		groups = strings.Split(*group, ",")
+	}
+	var buf bytes.Buffer
+	if err := c.Schema.WriteERD(&buf, *format, groups...); err != nil {
+		return err
+	}
+	return writeOutput(*out, buf.Bytes())
+}
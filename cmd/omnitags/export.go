@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	src := sourceFlags(fs)
+	format := fs.String("format", "", "output format: "+strings.Join(config.ExportFormats(), ", "))
+	out := fs.String("out", "", "output file, stdout when empty")
+	fs.Parse(args)
+
+	exporter, err := config.NewExporter(*format)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	c, err := config.LoadOmnitags(*src)
+	if err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.Export(&buf, c); err != nil {
+		return err
+	}
+	return writeOutput(*out, buf.Bytes())
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runGen(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("gen: missing target, expected one of: models, ddl, migration")
+	}
+
+	switch args[0] {
+	case "models":
+		return runGenModels(args[1:])
+	case "ddl":
+		return runGenDDL(args[1:])
+	case "migration":
+		return runGenMigration(args[1:])
+	default:
+		return fmt.Errorf("gen: unknown target %q", args[0])
+	}
+}
+
+func runGenModels(args []string) error {
+	fs := flag.NewFlagSet("gen models", flag.ExitOnError)
+	src := sourceFlags(fs)
+	out := fs.String("out", "model/omnitags", "output directory")
+	pkg := fs.String("pkg", "omnitags", "package name of the generated files")
+	fs.Parse(args)
+
+	c, err := config.LoadOmnitags(*src)
+	if err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	files, err := config.GenerateModels(c.Schema, *pkg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(*out, name)
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", path)
+	}
+	return nil
+}
+
+func runGenDDL(args []string) error {
+	fs := flag.NewFlagSet("gen ddl", flag.ExitOnError)
+	src := sourceFlags(fs)
+	dialectName := fs.String("dialect", "mysql", "SQL dialect: mysql or sqlite")
+	out := fs.String("out", "", "output file, defaults to stdout")
+	fs.Parse(args)
+
+	dialect, err := config.ParseDialect(*dialectName)
+	if err != nil {
+		return err
+	}
+
+	c, err := config.LoadOmnitags(*src)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*out, []byte(config.GenerateDDL(c.Schema, dialect)))
+}
+
+func runGenMigration(args []string) error {
+	fs := flag.NewFlagSet("gen migration", flag.ExitOnError)
+	oldFile := fs.String("old", "", "previous version of the environment file")
+	newFile := fs.String("new", "app.postman_environment.json", "current version of the environment file")
+	dialectName := fs.String("dialect", "mysql", "SQL dialect: mysql or sqlite")
+	name := fs.String("name", "omnitags", "migration name")
+	out := fs.String("out", "migrations", "output directory")
+	fs.Parse(args)
+
+	if *oldFile == "" {
+		return fmt.Errorf("gen migration: -old is required")
+	}
+
+	dialect, err := config.ParseDialect(*dialectName)
+	if err != nil {
+		return err
+	}
+
+	oldData, err := config.ReadEnvironmentFile(*oldFile)
+	if err != nil {
+		return err
+	}
+	newData, err := config.ReadEnvironmentFile(*newFile)
+	if err != nil {
+		return err
+	}
+
+	migration := config.GenerateMigration(config.ParseSchema(oldData), config.ParseSchema(newData), dialect)
+	if migration.Empty() {
+		fmt.Println("no schema changes")
+		return nil
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	base := filepath.Join(*out, fmt.Sprintf("%s_%s", time.Now().Format("20060102150405"), *name))
+	if err := writeOutput(base+".up.sql", []byte(migration.UpSQL())); err != nil {
+		return err
+	}
+	return writeOutput(base+".down.sql", []byte(migration.DownSQL()))
+}
+
+// writeOutput writes data to path, or to stdout when path is empty
+func writeOutput(path string, data []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Println("wrote", path)
+	return nil
+}
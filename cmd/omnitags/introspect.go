@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runIntrospect(args []string) error {
+	fs := flag.NewFlagSet("introspect", flag.ExitOnError)
+	driver := fs.String("driver", "mysql", "database driver: mysql or sqlite")
+	dsn := fs.String("dsn", "", "data source name; for mysql defaults to the DB settings of .env, for sqlite it is the database file")
+	group := fs.String("group", "a", "table group the tables are numbered in, e.g. a gives tabel_a<n> keys")
+	name := fs.String("name", "", "Postman environment name, defaults to the database or file name")
+	out := fs.String("out", "", "output environment file, stdout when empty")
+	report := fs.String("report", "", "write the mapping report as JSON to this file instead of a table on stderr")
+	fs.Parse(args)
+
+	db, err := openDatabase(*driver, *dsn)
+	if err != nil {
+		return err
+	}
+
+	s, mappings, err := config.IntrospectDatabase(db, *group)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *name != "":
+		s.Name = *name
+	case s.Name == "":
+		s.Name = strings.TrimSuffix(filepath.Base(*dsn), filepath.Ext(*dsn))
+	}
+	if len(s.Tables) == 0 {
+		return fmt.Errorf("introspect: the database has no tables")
+	}
+
+	data, err := s.MarshalEnvironment()
+	if err != nil {
+		return err
+	}
+	if err := writeOutput(*out, append(data, '\n')); err != nil {
+		return err
+	}
+
+	if *report != "" {
+		data, err := json.MarshalIndent(mappings, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeOutput(*report, append(data, '\n'))
+	}
+	return printMappings(mappings)
+}
+
+// openDatabase connects to the database to introspect
+func openDatabase(driver, dsn string) (*gorm.DB, error) {
+	cfg := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	switch driver {
+	case "mysql":
+		if dsn == "" {
+			return config.ConnectMySQL()
+		}
+		return gorm.Open(mysql.Open(dsn), cfg)
+	case "sqlite":
+		if dsn == "" {
+			return nil, fmt.Errorf("introspect: -dsn is required for sqlite")
+		}
+		if _, err := os.Stat(dsn); err != nil {
+			return nil, fmt.Errorf("introspect: %w", err)
+		}
+		return gorm.Open(sqlite.Open(dsn), cfg)
+	}
+	return nil, fmt.Errorf("introspect: unsupported driver %q, expected mysql or sqlite", driver)
+}
+
+// printMappings writes the mapping report as a table on stderr
+func printMappings(mappings []config.DatabaseMapping) error {
+	w := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tCOLUMN\tTYPE\tALIAS\tNOTE")
+	placeholders := 0
+	for _, m := range mappings {
+		column := m.Table
+		if m.Column != "" {
+			column += "." + m.Column
+		}
+		alias := m.Alias
+		if m.Placeholder {
+			alias += " (placeholder)"
+			placeholders++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Key, column, m.Type, alias, m.Note)
+	}
+	fmt.Fprintf(w, "\n%d keys, %d placeholder aliases to review\n", len(mappings), placeholders)
+	return w.Flush()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	src := sourceFlags(fs)
+	format := fs.String("format", "text", "output format: text or json")
+	failOn := fs.String("fail-on", "error", "exit non-zero on issues of this severity or worse: error, warning or info")
+	fs.Parse(args)
+
+	threshold, err := config.ParseSeverity(*failOn)
+	if err != nil {
+		return err
+	}
+
+	path, err := src.Resolve()
+	if err != nil {
+		return err
+	}
+	data, err := config.ReadEnvironmentFile(path)
+	if err != nil {
+		return err
+	}
+
+	c := config.NewConfig()
+	c.LoadData(data)
+	report := config.NewLintReport(path, c.Lint())
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	case "text":
+		for _, issue := range report.Issues {
+			fmt.Printf("%s: %s: %s [%s]\n", path, issue.Severity, issue.Message, issue.Rule)
+		}
+		fmt.Printf("%d errors, %d warnings, %d infos\n", report.Errors, report.Warnings, report.Infos)
+	default:
+		return fmt.Errorf("lint: unknown format %q", *format)
+	}
+
+	if report.Fails(threshold) {
+		return fmt.Errorf("lint: %s has issues of severity %s or worse", path, threshold)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Command omnitags provides tooling around the Omnitags Postman environment file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+const usage = `usage: omnitags <command> [flags]
+
+commands:
+  gen models     generate GORM structs from the environment file
+  gen ddl        generate CREATE TABLE statements for mysql or sqlite
+  gen migration  generate an up/down migration between two environment files
+  lint           report problems in the environment file
+  diff           report semantic changes between two environment files
+  introspect     write an environment file describing an existing mysql or sqlite database
+  export         write the resolved maps as php, ts, yaml, dotenv or jsonschema
+  erd            draw the tables, groups and inferred relations as a mermaid or dot ER diagram
+  refs           report tabel_* keys used in Go code and templates that the file does not define
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "gen":
+		err = runGen(os.Args[2:])
+	case "lint":
+		err = runLint(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "introspect":
+		err = runIntrospect(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "erd":
+		err = runERD(os.Args[2:])
+	case "refs":
+		err = runRefs(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "omnitags:", err)
+		os.Exit(1)
+	}
+}
+
+// sourceFlags registers -file, -env and -naming on fs, defaulting to OMNITAGSFILE, OMNITAGSENV and OMNITAGSNAMING.
+// The service's names -omnitags-file, -omnitags-env and -omnitags-naming are accepted as well.
+func sourceFlags(fs *flag.FlagSet) *config.OmnitagsSource {
+	src := config.OmnitagsSourceFromEnv()
+	for _, prefix := range []string{"", "omnitags-"} {
+		fs.StringVar(&src.Path, prefix+"file", src.Path, "Postman environment file or directory")
+		fs.StringVar(&src.Environment, prefix+"env", src.Environment, "environment name, e.g. dev, staging or prod")
+		fs.StringVar(&src.NamingFile, prefix+"naming", src.NamingFile, "JSON file overriding the naming conventions")
+	}
+	return &src
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func runRefs(args []string) error {
+	fs := flag.NewFlagSet("refs", flag.ExitOnError)
+	src := sourceFlags(fs)
+	format := fs.String("format", "text", "output format: text or json")
+	unused := fs.Bool("unused", false, "also list environment keys that nothing refers to")
+	tests := fs.Bool("tests", false, "also scan _test.go files")
+	fs.Parse(args)
+
+	roots := fs.Args()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	c, err := config.LoadOmnitags(*src)
+	if err != nil {
+		return err
+	}
+	report, err := c.CheckReferences(config.ReferenceOptions{Tests: *tests}, roots...)
+	if err != nil {
+		return err
+	}
+	if !*unused {
+		report.Unused = []string{}
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	case "text":
+		for _, ref := range report.Unknown {
+			fmt.Printf("%s:%d: unknown key %s", ref.File, ref.Line, ref.Key)
+			if ref.Suggestion != "" {
+				fmt.Printf(", did you mean %s?", ref.Suggestion)
+			}
+			fmt.Println()
+		}
+		for _, key := range report.Unused {
+			fmt.Printf("unused key %s\n", key)
+		}
+		fmt.Printf("%d unknown, %d unused\n", len(report.Unknown), len(report.Unused))
+	default:
+		return fmt.Errorf("refs: unknown format %q", *format)
+	}
+
+	if len(report.Unknown) > 0 {
+		return fmt.Errorf("refs: %d references to unknown keys", len(report.Unknown))
+	}
+	return nil
+}
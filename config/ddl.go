@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is a SQL flavour the DDL generator can target
+type Dialect string
+
+const (
+	DialectMySQL  Dialect = "mysql"
+	DialectSQLite Dialect = "sqlite"
+)
+
+// ParseDialect validates a dialect name
+func ParseDialect(name string) (Dialect, error) {
+	switch d := Dialect(strings.ToLower(name)); d {
+	case DialectMySQL, DialectSQLite:
+		return d, nil
+	}
+	return "", fmt.Errorf("unsupported dialect %q, expected mysql or sqlite", name)
+}
+
+// quote quotes an identifier for the dialect
+func (d Dialect) quote(name string) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// literal quotes a string literal
+func literal(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// columnType returns the column type, including enum constraints, for a field
+func (d Dialect) columnType(f *Field) string {
+	switch f.Kind() {
+	case KindID:
+		if d == DialectMySQL {
+			return "BIGINT UNSIGNED"
+		}
+		return "INTEGER"
+	case KindInt:
+		if d == DialectMySQL {
+			return "INT"
+		}
+		return "INTEGER"
+	case KindTime:
+		return "DATETIME"
+	case KindText:
+		return "TEXT"
+	case KindEnum:
+		values := make([]string, 0, len(f.Values))
+		for _, v := range f.EnumValues() {
+			values = append(values, literal(v))
+		}
+		if len(values) == 0 {
+			break
+		}
+		if d == DialectMySQL {
+			return "ENUM(" + strings.Join(values, ",") + ")"
+		}
+		return fmt.Sprintf("TEXT CHECK (%s IN (%s))", d.quote(f.Name), strings.Join(values, ","))
+	}
+	if d == DialectMySQL {
+		return "VARCHAR(255)"
+	}
+	return "TEXT"
+}
+
+// columnDefinition renders a full column definition within table t
+func (d Dialect) columnDefinition(t *Table, f *Field) string {
+	def := d.quote(f.Name) + " " + d.columnType(f)
+	if f == t.PrimaryKey() {
+		switch {
+		case f.Kind() == KindID && d == DialectMySQL:
+			def += " NOT NULL AUTO_INCREMENT"
+		case f.Kind() == KindID:
+			def += " PRIMARY KEY AUTOINCREMENT"
+		default:
+			def += " NOT NULL"
+		}
+		return def
+	}
+	return def + " NULL"
+}
+
+// CreateTable renders the CREATE TABLE statement of a table
+func (d Dialect) CreateTable(t *Table) string {
+	var columns []string
+	for _, f := range t.Fields {
+		if f.Name == "" {
+			continue
+		}
+		columns = append(columns, "  "+d.columnDefinition(t, f))
+	}
+	if pk := t.PrimaryKey(); pk != nil && (d == DialectMySQL || pk.Kind() != KindID) {
+		columns = append(columns, fmt.Sprintf("  PRIMARY KEY (%s)", d.quote(pk.Name)))
+	}
+
+	header := "-- " + t.Key
+	if t.Alias != "" {
+		header += ": " + commentText(t.Alias)
+	}
+	stmt := fmt.Sprintf("%s\nCREATE TABLE %s (\n%s\n)", header, d.quote(t.Name), strings.Join(columns, ",\n"))
+	if d == DialectMySQL {
+		stmt += " ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	}
+	return stmt + ";\n"
+}
+
+// DropTable renders the DROP TABLE statement of a table
+func (d Dialect) DropTable(t *Table) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", d.quote(t.Name))
+}
+
+// GenerateDDL renders CREATE TABLE statements for every table of the schema.
+// Tables without a name or fields cannot be created and are listed as comments.
+func GenerateDDL(s *Schema, d Dialect) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Generated by omnitags gen ddl for %s. DO NOT EDIT.\n", d)
+	for _, t := range s.Tables {
+		b.WriteString("\n")
+		if t.Name == "" || t.PrimaryKey() == nil {
+			fmt.Fprintf(&b, "-- %s (%s) has no fields, skipped\n", t.Key, t.Name)
+			continue
+		}
+		b.WriteString(d.CreateTable(t))
+	}
+	return b.String()
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind says what happened to an entry between two versions of the environment file
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeRenamed ChangeKind = "renamed"
+	ChangeAlias   ChangeKind = "alias"
+	ChangeType    ChangeKind = "type"
+	ChangeValue   ChangeKind = "value"
+)
+
+// Change is one semantic difference between two schema versions.
+// Migration is set when the database has to change, Frontend when forms, labels or keys used by views change,
+// and Breaking when existing data, routes or input names stop working.
+type Change struct {
+	Kind      ChangeKind `json:"kind"`
+	Target    string     `json:"target"`
+	Key       string     `json:"key"`
+	Old       string     `json:"old,omitempty"`
+	New       string     `json:"new,omitempty"`
+	Breaking  bool       `json:"breaking"`
+	Migration bool       `json:"migration"`
+	Frontend  bool       `json:"frontend"`
+}
+
+// String describes the change in one line
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %s added: %q", c.Target, c.Key, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %s removed: %q", c.Target, c.Key, c.Old)
+	case ChangeAlias:
+		return fmt.Sprintf("%s %s alias changed: %q -> %q", c.Target, c.Key, c.Old, c.New)
+	case ChangeType:
+		return fmt.Sprintf("%s %s type changed: %s -> %s", c.Target, c.Key, c.Old, c.New)
+	case ChangeValue:
+		return fmt.Sprintf("%s %s changed: %q -> %q", c.Target, c.Key, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s %s renamed: %q -> %q", c.Target, c.Key, c.Old, c.New)
+}
+
+// SchemaDiff is the list of changes between two schema versions with their overall impact
+type SchemaDiff struct {
+	Changes   []Change `json:"changes"`
+	Breaking  int      `json:"breaking"`
+	Migration bool     `json:"migration"`
+	Frontend  bool     `json:"frontend"`
+}
+
+// Empty reports whether the two versions describe the same schema
+func (d SchemaDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// differ collects changes while walking two schemas
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(c Change) {
+	d.changes = append(d.changes, c)
+}
+
+// DiffSchemas compares two versions of the environment file.
+// Like GenerateMigration, tables, fields and enum values are matched by key, so a changed value is a rename.
+func DiffSchemas(from, to *Schema) SchemaDiff {
+	d := &differ{}
+
+	d.diffSettings(from.Settings, to.Settings)
+	d.diffGroups(from.GroupInfo, to.GroupInfo)
+	for _, newTable := range to.Tables {
+		if oldTable := from.Table(newTable.Code()); oldTable != nil {
+			d.diffTable(oldTable, newTable)
+			continue
+		}
+		d.add(Change{Kind: ChangeAdded, Target: "table", Key: newTable.Key, New: newTable.Name, Migration: true, Frontend: true})
+	}
+	for _, oldTable := range from.Tables {
+		if to.Table(oldTable.Code()) == nil {
+			d.add(Change{Kind: ChangeRemoved, Target: "table", Key: oldTable.Key, Old: oldTable.Name, Breaking: true, Migration: true, Frontend: true})
+		}
+	}
+
+	diff := SchemaDiff{Changes: d.changes}
+	if diff.Changes == nil {
+		diff.Changes = []Change{}
+	}
+	for _, c := range diff.Changes {
+		if c.Breaking {
+			diff.Breaking++
+		}
+		diff.Migration = diff.Migration || c.Migration
+		diff.Frontend = diff.Frontend || c.Frontend
+	}
+	return diff
+}
+
+// diffSettings compares the entries that do not describe tables, e.g. base_url
+func (d *differ) diffSettings(from, to map[string]string) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, exists := from[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldValue, hadValue := from[key]
+		newValue, hasValue := to[key]
+		switch {
+		case !hadValue:
+			d.add(Change{Kind: ChangeAdded, Target: "setting", Key: key, New: newValue})
+		case !hasValue:
+			d.add(Change{Kind: ChangeRemoved, Target: "setting", Key: key, Old: oldValue, Breaking: true, Frontend: true})
+		case oldValue != newValue:
+			d.add(Change{Kind: ChangeValue, Target: "setting", Key: key, Old: oldValue, New: newValue, Frontend: true})
+		}
+	}
+}
+
+// diffGroups compares the labels and orders of the table groups, e.g. tabel_f_alias and tabel_f_order
+func (d *differ) diffGroups(from, to map[string]GroupInfo) {
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, exists := from[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	order := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	for _, name := range names {
+		oldInfo, newInfo := from[name], to[name]
+		if oldInfo.Label != newInfo.Label {
+			d.add(Change{Kind: ChangeAlias, Target: "group", Key: "tabel_" + name + "_alias", Old: oldInfo.Label, New: newInfo.Label, Frontend: true})
+		}
+		if oldInfo.Order != newInfo.Order {
+			d.add(Change{Kind: ChangeValue, Target: "group", Key: "tabel_" + name + "_order", Old: order(oldInfo.Order), New: order(newInfo.Order), Frontend: true})
+		}
+	}
+}
+
+// diffTable compares two versions of the same tabel_* key
+func (d *differ) diffTable(from, to *Table) {
+	if from.Name != to.Name {
+		d.add(Change{Kind: ChangeRenamed, Target: "table", Key: to.Key, Old: from.Name, New: to.Name, Breaking: true, Migration: true, Frontend: true})
+	}
+	if from.Alias != to.Alias {
+		d.add(Change{Kind: ChangeAlias, Target: "table", Key: to.Key + "_alias", Old: from.Alias, New: to.Alias, Frontend: true})
+	}
+	if from.Alias2 != to.Alias2 {
+		d.add(Change{Kind: ChangeAlias, Target: "table", Key: to.Key + "_alias2", Old: from.Alias2, New: to.Alias2, Frontend: true})
+	}
+
+	for _, newField := range to.Fields {
+		if oldField := fieldByKey(from, newField.Key); oldField != nil {
+			d.diffField(oldField, newField)
+			continue
+		}
+		d.add(Change{Kind: ChangeAdded, Target: "field", Key: newField.Key, New: to.Name + "." + newField.Name, Migration: true, Frontend: true})
+	}
+	for _, oldField := range from.Fields {
+		if fieldByKey(to, oldField.Key) == nil {
+			d.add(Change{Kind: ChangeRemoved, Target: "field", Key: oldField.Key, Old: from.Name + "." + oldField.Name, Breaking: true, Migration: true, Frontend: true})
+		}
+	}
+}
+
+// diffField compares two versions of the same tabel_*_field* key
+func (d *differ) diffField(from, to *Field) {
+	if from.Name != to.Name {
+		d.add(Change{Kind: ChangeRenamed, Target: "field", Key: to.Key, Old: from.Name, New: to.Name, Breaking: true, Migration: true, Frontend: true})
+	}
+	if from.Alias != to.Alias {
+		d.add(Change{Kind: ChangeAlias, Target: "field", Key: to.Key + "_alias", Old: from.Alias, New: to.Alias, Frontend: true})
+	}
+	if from.Ref != to.Ref {
+		// No foreign keys are generated, but the ER diagrams and the relations of the admin routes change
+		d.add(Change{Kind: ChangeValue, Target: "field", Key: to.Key + "_ref", Old: from.Ref, New: to.Ref, Frontend: true})
+	}
+	if from.Kind() != to.Kind() {
+		d.add(Change{Kind: ChangeType, Target: "field", Key: to.Key, Old: string(from.Kind()), New: string(to.Kind()), Breaking: true, Migration: true})
+	}
+
+	for _, newValue := range to.Values {
+		oldValue := valueByKey(from, newValue.Key)
+		switch {
+		case oldValue == nil:
+			d.add(Change{Kind: ChangeAdded, Target: "value", Key: newValue.Key, New: newValue.Value, Migration: true, Frontend: true})
+		case oldValue.Value != newValue.Value:
+			// Rows holding the old value no longer pass the constraint
+			d.add(Change{Kind: ChangeValue, Target: "value", Key: newValue.Key, Old: oldValue.Value, New: newValue.Value, Breaking: true, Migration: true, Frontend: true})
+		}
+		if oldValue != nil && oldValue.Alias != newValue.Alias {
+			d.add(Change{Kind: ChangeAlias, Target: "value", Key: newValue.Key + "_alias", Old: oldValue.Alias, New: newValue.Alias, Frontend: true})
+		}
+	}
+	for _, oldValue := range from.Values {
+		if valueByKey(to, oldValue.Key) == nil {
+			d.add(Change{Kind: ChangeRemoved, Target: "value", Key: oldValue.Key, Old: oldValue.Value, Breaking: true, Migration: true, Frontend: true})
+		}
+	}
+}
+
+func valueByKey(f *Field, key string) *EnumValue {
+	for i := range f.Values {
+		if f.Values[i].Key == key {
+			return &f.Values[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// diffBase is the version the diff and migration tests start from
+var diffBase = []string{
+	"base_url", "http://localhost",
+	"tabel_f3", "transaksi",
+	"tabel_f3_alias", "Transaksi",
+	"tabel_f3_field1", "id",
+	"tabel_f3_field2", "nama",
+	"tabel_f3_field2_alias", "Nama",
+	"tabel_f3_field3", "metode",
+	"tabel_f3_field3_value1", "cash",
+	"tabel_f3_field3_value1_alias", "Tunai",
+	"tabel_f3_field3_value2", "transfer",
+}
+
+// diffVersion returns diffBase with the given keys replaced, removed when the value is "" or added
+func diffVersion(changes ...string) *Schema {
+	values := map[string]string{}
+	var order []string
+	for i := 0; i+1 < len(diffBase); i += 2 {
+		values[diffBase[i]] = diffBase[i+1]
+		order = append(order, diffBase[i])
+	}
+	for i := 0; i+1 < len(changes); i += 2 {
+		if _, exists := values[changes[i]]; !exists {
+			order = append(order, changes[i])
+		}
+		values[changes[i]] = changes[i+1]
+	}
+	var pairs []string
+	for _, key := range order {
+		if values[key] != "" {
+			pairs = append(pairs, key, values[key])
+		}
+	}
+	return ParseSchema(environment(pairs...))
+}
+
+func TestDiffSchemas(t *testing.T) {
+	type change struct {
+		Kind      ChangeKind
+		Target    string
+		Key       string
+		Breaking  bool
+		Migration bool
+	}
+	tests := []struct {
+		name    string
+		changes []string
+		want    []change
+	}{
+		{"no changes", nil, []change{}},
+		{"setting changed", []string{"base_url", "https://example.com"}, []change{{ChangeValue, "setting", "base_url", false, false}}},
+		{"setting removed", []string{"base_url", ""}, []change{{ChangeRemoved, "setting", "base_url", true, false}}},
+		{"table added", []string{"tabel_f4", "pelanggan", "tabel_f4_field1", "id"}, []change{{ChangeAdded, "table", "tabel_f4", false, true}}},
+		{"table renamed", []string{"tabel_f3", "transaksi_baru"}, []change{{ChangeRenamed, "table", "tabel_f3", true, true}}},
+		{"table alias", []string{"tabel_f3_alias", "Transactions"}, []change{{ChangeAlias, "table", "tabel_f3_alias", false, false}}},
+		{"field added", []string{"tabel_f3_field4", "harga"}, []change{{ChangeAdded, "field", "tabel_f3_field4", false, true}}},
+		{"field removed", []string{"tabel_f3_field2", "", "tabel_f3_field2_alias", ""}, []change{{ChangeRemoved, "field", "tabel_f3_field2", true, true}}},
+		{"field renamed", []string{"tabel_f3_field2", "nama_pembeli"}, []change{{ChangeRenamed, "field", "tabel_f3_field2", true, true}}},
+		{"field type", []string{"tabel_f3_field2", "tgl_bayar"}, []change{
+			{ChangeRenamed, "field", "tabel_f3_field2", true, true},
+			{ChangeType, "field", "tabel_f3_field2", true, true},
+		}},
+		{"field alias", []string{"tabel_f3_field2_alias", "Name"}, []change{{ChangeAlias, "field", "tabel_f3_field2_alias", false, false}}},
+		{"field reference", []string{"tabel_f3_field2_ref", "c2"}, []change{{ChangeValue, "field", "tabel_f3_field2_ref", false, false}}},
+		{"group label", []string{"tabel_f_alias", "Finance"}, []change{{ChangeAlias, "group", "tabel_f_alias", false, false}}},
+		{"group order", []string{"tabel_f_order", "2"}, []change{{ChangeValue, "group", "tabel_f_order", false, false}}},
+		{"enum value added", []string{"tabel_f3_field3_value3", "qris"}, []change{{ChangeAdded, "value", "tabel_f3_field3_value3", false, true}}},
+		{"enum value changed", []string{"tabel_f3_field3_value2", "bank"}, []change{{ChangeValue, "value", "tabel_f3_field3_value2", true, true}}},
+		{"enum value removed", []string{"tabel_f3_field3_value2", ""}, []change{{ChangeRemoved, "value", "tabel_f3_field3_value2", true, true}}},
+		{"enum value alias", []string{"tabel_f3_field3_value1_alias", "Cash"}, []change{{ChangeAlias, "value", "tabel_f3_field3_value1_alias", false, false}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffSchemas(diffVersion(), diffVersion(tt.changes...))
+			got := []change{}
+			breaking := 0
+			for _, c := range diff.Changes {
+				got = append(got, change{c.Kind, c.Target, c.Key, c.Breaking, c.Migration})
+				if c.Breaking {
+					breaking++
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %+v, want %+v", got, tt.want)
+			}
+			if diff.Breaking != breaking || diff.Empty() != (len(tt.want) == 0) {
+				t.Errorf("Breaking = %d, Empty = %v for %d changes", diff.Breaking, diff.Empty(), len(got))
+			}
+		})
+	}
+}
+
+func TestDiffSchemasTableRemoved(t *testing.T) {
+	from := diffVersion("tabel_f4", "pelanggan", "tabel_f4_field1", "id")
+	diff := DiffSchemas(from, diffVersion())
+	if len(diff.Changes) != 1 {
+		t.Fatalf("changes = %+v, want one", diff.Changes)
+	}
+	if c := diff.Changes[0]; c.Kind != ChangeRemoved || c.Key != "tabel_f4" || !c.Breaking || !diff.Migration {
+		t.Errorf("change = %+v, want a breaking removal of tabel_f4", c)
+	}
+}
+
+func TestDiffSchemasFrontendImpact(t *testing.T) {
+	from := diffVersion("tabel_f_alias", "Finance", "tabel_f_order", "1", "tabel_f3_field2_ref", "c2")
+	tests := []struct {
+		name    string
+		changes []string
+		key     string
+		old     string
+		new     string
+	}{
+		{"group label changed", []string{"tabel_f_alias", "Keuangan"}, "tabel_f_alias", "Finance", "Keuangan"},
+		{"group label removed", []string{"tabel_f_alias", ""}, "tabel_f_alias", "Finance", ""},
+		{"group order changed", []string{"tabel_f_order", "3"}, "tabel_f_order", "1", "3"},
+		{"group order removed", []string{"tabel_f_order", ""}, "tabel_f_order", "1", ""},
+		{"reference changed", []string{"tabel_f3_field2_ref", "users.id"}, "tabel_f3_field2_ref", "c2", "users.id"},
+		{"reference removed", []string{"tabel_f3_field2_ref", ""}, "tabel_f3_field2_ref", "c2", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := append([]string{"tabel_f_alias", "Finance", "tabel_f_order", "1", "tabel_f3_field2_ref", "c2"}, tt.changes...)
+			diff := DiffSchemas(from, diffVersion(changes...))
+			if len(diff.Changes) != 1 {
+				t.Fatalf("changes = %+v, want one", diff.Changes)
+			}
+			c := diff.Changes[0]
+			if c.Key != tt.key || c.Old != tt.old || c.New != tt.new {
+				t.Errorf("change = %+v, want %s %q -> %q", c, tt.key, tt.old, tt.new)
+			}
+			if !c.Frontend || !diff.Frontend || c.Migration || c.Breaking {
+				t.Errorf("change = %+v, want a frontend-only change", c)
+			}
+		})
+	}
+}
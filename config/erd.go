@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// erd is what a diagram shows: the drawn groups, the relations between their tables
+// and every referencing field, also those whose target is not drawn
+type erd struct {
+	Groups    []*Group
+	Relations []*Relation
+	Foreign   map[*Field]bool
+}
+
+// erdWriters holds the ER diagram formats
+var erdWriters = map[string]func(w io.Writer, d *erd) error{
+	"mermaid": writeMermaid,
+	"dot":     writeDOT,
+}
+
+// ERDFormats lists the ER diagram formats
+func ERDFormats() []string {
+	formats := make([]string, 0, len(erdWriters))
+	for format := range erdWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// WriteERD draws the tables, grouped as in TableGroups, and their Relations as a Mermaid or DOT ER diagram.
+// With group names only those groups are drawn, together with the relations between their tables.
+func (s *Schema) WriteERD(w io.Writer, format string, groups ...string) error {
+	write, known := erdWriters[format]
+	if !known {
+		return fmt.Errorf("unknown diagram format %q, expected one of %s", format, strings.Join(ERDFormats(), ", "))
+	}
+
+	drawn := s.TableGroups()
+	if len(groups) > 0 {
+		drawn = nil
+		for _, name := range groups {
+			g := s.TableGroup(name)
+			if g == nil {
+				return fmt.Errorf("unknown table group %q", name)
+			}
+			drawn = append(drawn, g)
+		}
+	}
+
+	tables := make(map[*Table]bool)
+	for _, g := range drawn {
+		for _, t := range g.Tables {
+			tables[t] = t.Name != ""
+		}
+	}
+	d := &erd{Groups: drawn, Foreign: make(map[*Field]bool)}
+	for _, r := range s.Relations() {
+		d.Foreign[r.Field] = true
+		if tables[r.Table] && tables[r.Target] {
+			d.Relations = append(d.Relations, r)
+		}
+	}
+	return write(w, d)
+}
+
+// writeMermaid writes a Mermaid erDiagram, each group introduced by a comment with its label
+func writeMermaid(w io.Writer, d *erd) error {
+	var buf bytes.Buffer
+	buf.WriteString("erDiagram\n")
+	for _, g := range d.Groups {
+		fmt.Fprintf(&buf, "    %%%% %s (%s)\n", g.Label, g.Name)
+		for _, t := range g.Tables {
+			if t.Name == "" {
+				continue
+			}
+			if len(t.Fields) == 0 {
+				fmt.Fprintf(&buf, "    %s\n", t.Name)
+				continue
+			}
+			fmt.Fprintf(&buf, "    %s {\n", t.Name)
+			pk := t.PrimaryKey()
+			for _, f := range t.Fields {
+				if f.Name == "" {
+					continue
+				}
+				var keys []string
+				if f == pk {
+					keys = append(keys, "PK")
+				}
+				if d.Foreign[f] {
+					keys = append(keys, "FK")
+				}
+				fmt.Fprintf(&buf, "        %s %s", f.Kind(), f.Name)
+				if len(keys) > 0 {
+					fmt.Fprintf(&buf, " %s", strings.Join(keys, ","))
+				}
+				if f.Alias != "" {
+					fmt.Fprintf(&buf, " %q", strings.ReplaceAll(f.Alias, `"`, "'"))
+				}
+				buf.WriteString("\n")
+			}
+			buf.WriteString("    }\n")
+		}
+	}
+	for _, r := range d.Relations {
+		fmt.Fprintf(&buf, "    %s ||--o{ %s : %q\n", r.Target.Name, r.Table.Name, r.Field.Name)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// dotEscaper escapes the characters with a meaning in DOT record labels
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+
+// writeDOT writes a Graphviz digraph with one cluster per group and one record node per table.
+// Edges run from the referencing field to the referenced one.
+func writeDOT(w io.Writer, d *erd) error {
+	var buf bytes.Buffer
+	buf.WriteString("digraph omnitags {\n")
+	buf.WriteString("    rankdir=LR;\n")
+	buf.WriteString("    node [shape=record, fontname=\"Helvetica\", fontsize=10];\n")
+	buf.WriteString("    edge [arrowhead=tee, arrowtail=crow, dir=both];\n")
+	for _, g := range d.Groups {
+		fmt.Fprintf(&buf, "    subgraph \"cluster_%s\" {\n", g.Name)
+		fmt.Fprintf(&buf, "        label=\"%s\";\n", dotEscaper.Replace(g.Label))
+		for _, t := range g.Tables {
+			if t.Name == "" {
+				continue
+			}
+			title := t.Name
+			if t.Alias != "" {
+				title += `\n` + dotEscaper.Replace(t.Alias)
+			}
+			rows := []string{title}
+			pk := t.PrimaryKey()
+			for _, f := range t.Fields {
+				if f.Name == "" {
+					continue
+				}
+				row := fmt.Sprintf("<%s> %s : %s", f.Name, dotEscaper.Replace(f.Name), f.Kind())
+				if f == pk {
+					row += " (PK)"
+				}
+				if d.Foreign[f] {
+					row += " (FK)"
+				}
+				rows = append(rows, row+`\l`)
+			}
+			fmt.Fprintf(&buf, "        \"%s\" [label=\"{%s}\"];\n", t.Name, strings.Join(rows, "|"))
+		}
+		buf.WriteString("    }\n")
+	}
+	for _, r := range d.Relations {
+		fmt.Fprintf(&buf, "    \"%s\":\"%s\" -> \"%s\":\"%s\";\n", r.Table.Name, r.Field.Name, r.Target.Name, r.ToField.Name)
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
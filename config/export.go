@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Exporter renders the resolved Omnitags maps in another format
+type Exporter interface {
+	Export(w io.Writer, c *Omnitags) error
+}
+
+// ExporterFunc adapts a function to the Exporter interface
+type ExporterFunc func(w io.Writer, c *Omnitags) error
+
+// Export calls f(w, c)
+func (f ExporterFunc) Export(w io.Writer, c *Omnitags) error {
+	return f(w, c)
+}
+
+// exporters holds the built-in formats
+var exporters = map[string]Exporter{
+	"php":        ExporterFunc(exportPHP),
+	"ts":         ExporterFunc(exportTypeScript),
+	"yaml":       ExporterFunc(exportYAML),
+	"dotenv":     ExporterFunc(exportDotenv),
+	"jsonschema": ExporterFunc(exportJSONSchema),
+}
+
+// NewExporter returns the exporter of a format
+func NewExporter(format string) (Exporter, error) {
+	if e, exists := exporters[format]; exists {
+		return e, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q, expected one of %s", format, strings.Join(ExportFormats(), ", "))
+}
+
+// ExportFormats lists the built-in formats
+func ExportFormats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// exportedMap is one named map of the config with its keys in sorted order, numeric order for v
+type exportedMap struct {
+	Name   string
+	Keys   []string
+	Values map[string]string
+}
+
+// exportedMaps returns the maps shared with the PHP and JavaScript sides.
+// Secrets never reach these maps, so exports are safe to commit.
+func (c *Omnitags) exportedMaps() []exportedMap {
+	// V is indexed by section number, its keys keep numeric order so 10 follows 9
+	sections := make([]int, 0, len(c.V))
+	for i := range c.V {
+		sections = append(sections, i)
+	}
+	sort.Ints(sections)
+	v := make(map[string]string, len(c.V))
+	vKeys := make([]string, 0, len(c.V))
+	for _, i := range sections {
+		v[strconv.Itoa(i)] = c.V[i]
+		vKeys = append(vKeys, strconv.Itoa(i))
+	}
+	tl := make(map[string]string, len(c.TL))
+	for code := range c.TL {
+		tl[code] = c.Aliases["tabel_"+code]
+	}
+
+	maps := []exportedMap{
+		{Name: "aliases", Values: c.Aliases},
+		{Name: "vinput", Values: c.VInput},
+		{Name: "vpost", Values: c.VPost},
+		{Name: "vget", Values: c.VGet},
+		{Name: "flash1msg", Values: c.Flash1Msg},
+		{Name: "flash", Values: c.Flash},
+		{Name: "flashfunc", Values: c.FlashFunc},
+		{Name: "flashmsg", Values: c.FlashMsg},
+		{Name: "vuploadpath", Values: c.VUploadPath},
+		{Name: "views", Values: c.Views},
+		{Name: "titles", Values: c.Titles},
+		{Name: "v", Keys: vKeys, Values: v},
+		{Name: "tl", Values: tl},
+	}
+	for i := range maps {
+		if maps[i].Keys != nil {
+			continue
+		}
+		for key := range maps[i].Values {
+			maps[i].Keys = append(maps[i].Keys, key)
+		}
+		sort.Strings(maps[i].Keys)
+	}
+	return maps
+}
+
+// jsonString encodes s as a JSON string literal without HTML escaping
+func jsonString(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// phpString quotes s as a single-quoted PHP string
+func phpString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+const exportHeader = "Generated by omnitags export. DO NOT EDIT."
+
+// exportPHP writes a CodeIgniter config file, one $config entry per map
+func exportPHP(w io.Writer, c *Omnitags) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "<?php\n// %s\ndefined('BASEPATH') OR exit('No direct script access allowed');\n", exportHeader)
+	for _, m := range c.exportedMaps() {
+		fmt.Fprintf(b, "\n$config[%s] = [\n", phpString(m.Name))
+		for _, key := range m.Keys {
+			fmt.Fprintf(b, "\t%s => %s,\n", phpString(key), phpString(m.Values[key]))
+		}
+		b.WriteString("];\n")
+	}
+	return b.Flush()
+}
+
+// exportTypeScript writes one constant object per map
+func exportTypeScript(w io.Writer, c *Omnitags) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "// %s\n", exportHeader)
+	for _, m := range c.exportedMaps() {
+		fmt.Fprintf(b, "\nexport const %s = {\n", m.Name)
+		for _, key := range m.Keys {
+			fmt.Fprintf(b, "\t%s: %s,\n", jsonString(key), jsonString(m.Values[key]))
+		}
+		b.WriteString("} as const;\n")
+	}
+	b.WriteString("\nexport type OmnitagsKey = keyof typeof aliases;\n")
+	return b.Flush()
+}
+
+// exportYAML writes one mapping per map, with every scalar double-quoted
+func exportYAML(w io.Writer, c *Omnitags) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "# %s\n", exportHeader)
+	for _, m := range c.exportedMaps() {
+		if len(m.Keys) == 0 {
+			fmt.Fprintf(b, "%s: {}\n", m.Name)
+			continue
+		}
+		fmt.Fprintf(b, "%s:\n", m.Name)
+		for _, key := range m.Keys {
+			fmt.Fprintf(b, "  %s: %s\n", jsonString(key), jsonString(m.Values[key]))
+		}
+	}
+	return b.Flush()
+}
+
+// dotenvName turns a map and key into an upper-case variable name
+func dotenvName(mapName, key string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, "OMNITAGS_"+mapName+"_"+key)
+	return strings.ToUpper(name)
+}
+
+// exportDotenv writes OMNITAGS_<MAP>_<KEY>="value" lines
+func exportDotenv(w io.Writer, c *Omnitags) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "# %s\n", exportHeader)
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`)
+	for _, m := range c.exportedMaps() {
+		fmt.Fprintf(b, "\n# %s\n", m.Name)
+		for _, key := range m.Keys {
+			fmt.Fprintf(b, "%s=\"%s\"\n", dotenvName(m.Name, key), quote.Replace(m.Values[key]))
+		}
+	}
+	return b.Flush()
+}
+
+// exportJSONSchema writes a JSON Schema that pins every map entry to its resolved value
+func exportJSONSchema(w io.Writer, c *Omnitags) error {
+	type property struct {
+		Const string `json:"const"`
+	}
+	type mapSchema struct {
+		Type                 string              `json:"type"`
+		Properties           map[string]property `json:"properties"`
+		Required             []string            `json:"required"`
+		AdditionalProperties bool                `json:"additionalProperties"`
+	}
+
+	properties := make(map[string]mapSchema)
+	var required []string
+	for _, m := range c.exportedMaps() {
+		ms := mapSchema{Type: "object", Properties: make(map[string]property), Required: m.Keys}
+		if ms.Required == nil {
+			ms.Required = []string{}
+		}
+		for _, key := range m.Keys {
+			ms.Properties[key] = property{Const: m.Values[key]}
+		}
+		properties[m.Name] = ms
+		required = append(required, m.Name)
+	}
+
+	doc := map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "Omnitags",
+		"description":          exportHeader,
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
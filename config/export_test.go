@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/joho/godotenv"
+)
+
+// exportTestOmnitags has twelve view sections, so string order would put 10 before 2
+func exportTestOmnitags() *Omnitags {
+	return loadTestOmnitags(environment(
+		"view_sections", "12",
+		"base_url", "http://localhost/$app",
+		"tabel_a1", "ot_website",
+		"tabel_a1_alias", `Website "utama" baru`,
+		"tabel_a1_field1", "id",
+		"tabel_a1_field2", "judul",
+		"tabel_a1_field2_alias", "Judul\nHalaman",
+	))
+}
+
+func export(t *testing.T, format string, c *Omnitags) string {
+	t.Helper()
+	e, err := NewExporter(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := e.Export(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestExportSectionOrder(t *testing.T) {
+	c := exportTestOmnitags()
+	patterns := map[string]string{
+		"php":    `(?m)^\t'(\d+)' => `,
+		"ts":     `(?m)^\t"(\d+)": `,
+		"yaml":   `(?m)^  "(\d+)": `,
+		"dotenv": `(?m)^OMNITAGS_V_(\d+)=`,
+	}
+	for format, pattern := range patterns {
+		var got []string
+		for _, m := range regexp.MustCompile(pattern).FindAllStringSubmatch(export(t, format, c), -1) {
+			got = append(got, m[1])
+		}
+		if want := "1 2 3 4 5 6 7 8 9 10 11 12"; strings.Join(got, " ") != want {
+			t.Errorf("%s sections = %v, want %s", format, got, want)
+		}
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	c := exportTestOmnitags()
+	maps := c.exportedMaps()
+
+	t.Run("jsonschema", func(t *testing.T) {
+		var doc struct {
+			Properties map[string]struct {
+				Properties map[string]struct {
+					Const string `json:"const"`
+				} `json:"properties"`
+				Required []string `json:"required"`
+			} `json:"properties"`
+		}
+		if err := json.Unmarshal([]byte(export(t, "jsonschema", c)), &doc); err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range maps {
+			schema := doc.Properties[m.Name]
+			if len(schema.Properties) != len(m.Values) || strings.Join(schema.Required, ",") != strings.Join(m.Keys, ",") {
+				t.Errorf("%s has %d properties required as %v, want %d in the order %v", m.Name, len(schema.Properties), schema.Required, len(m.Values), m.Keys)
+			}
+			for key, value := range m.Values {
+				if got := schema.Properties[key].Const; got != value {
+					t.Errorf("%s[%s] = %q, want %q", m.Name, key, got, value)
+				}
+			}
+		}
+	})
+
+	t.Run("dotenv", func(t *testing.T) {
+		vars, err := godotenv.Unmarshal(export(t, "dotenv", c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, m := range maps {
+			for key, value := range m.Values {
+				count++
+				if got := vars[dotenvName(m.Name, key)]; got != value {
+					t.Errorf("%s = %q, want %q", dotenvName(m.Name, key), got, value)
+				}
+			}
+		}
+		if len(vars) != count {
+			t.Errorf("%d variables, want %d", len(vars), count)
+		}
+	})
+}
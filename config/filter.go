@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+	"gorm.io/gorm/clause"
+)
+
+// FilterOp is the comparison a filter parameter applies to its field
+type FilterOp string
+
+const (
+	FilterMin   FilterOp = "min"
+	FilterMax   FilterOp = "max"
+	FilterEqual FilterOp = "equal"
+	FilterLike  FilterOp = "like"
+)
+
+// FilterParam is a query parameter accepted by the list routes of a table, e.g. min_harga
+type FilterParam struct {
+	Param string   `json:"param"`
+	Field *Field   `json:"-"`
+	Op    FilterOp `json:"op"`
+}
+
+// filterDateLayouts are the accepted formats of time filters, most specific first
+var filterDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// FilterParams returns the query parameters of table t, named after the VGet entries of its fields:
+// <field>_filter1 and <field>_filter2 bound numbers and times, <field> matches a value or, for text, a substring.
+// Sensitive fields cannot be filtered.
+func (c *Omnitags) FilterParams(t *Table) []FilterParam {
+	var params []FilterParam
+	for _, f := range t.Fields {
+		if f.Name == "" || f.Sensitive() {
+			continue
+		}
+		kind := f.Kind()
+		if kind == KindID || kind == KindInt || kind == KindTime {
+			if param := c.VGet[f.Key+"_filter1"]; param != "" {
+				params = append(params, FilterParam{Param: param, Field: f, Op: FilterMin})
+			}
+			if param := c.VGet[f.Key+"_filter2"]; param != "" {
+				params = append(params, FilterParam{Param: param, Field: f, Op: FilterMax})
+			}
+		}
+		if param := c.VGet[f.Key]; param != "" {
+			op := FilterEqual
+			if kind == KindString || kind == KindText {
+				op = FilterLike
+			}
+			params = append(params, FilterParam{Param: param, Field: f, Op: op})
+		}
+	}
+	return params
+}
+
+// BuildFilter turns the filter parameters present in query into GORM clauses for table t.
+// Values are coerced to the field type; values that cannot be are returned as field errors.
+func (c *Omnitags) BuildFilter(t *Table, query url.Values) ([]clause.Expression, FieldErrors) {
+	var exprs []clause.Expression
+	var errs FieldErrors
+	bounds := make(map[string][2]interface{})
+
+	for _, p := range c.FilterParams(t) {
+		raw := strings.TrimSpace(query.Get(p.Param))
+		if raw == "" {
+			continue
+		}
+		column := clause.Column{Name: p.Field.Name}
+
+		if p.Op == FilterLike {
+			exprs = append(exprs, Contains{Column: column, Value: raw})
+			continue
+		}
+
+		value, dateOnly, err := coerceFilterValue(p.Field, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: p.Field.Name, Key: p.Field.Key, Alias: p.Field.Alias, Param: p.Param, Value: raw, Message: err.Error(), Allowed: allowedFilterValues(p.Field)})
+			continue
+		}
+
+		b := bounds[p.Field.Name]
+		switch p.Op {
+		case FilterMin:
+			exprs = append(exprs, clause.Gte{Column: column, Value: value})
+			b[0] = value
+		case FilterMax:
+			if dateOnly {
+				// A date-only upper bound includes the whole day
+				exprs = append(exprs, clause.Lt{Column: column, Value: value.(time.Time).AddDate(0, 0, 1)})
+			} else {
+				exprs = append(exprs, clause.Lte{Column: column, Value: value})
+			}
+			b[1] = value
+		default:
+			exprs = append(exprs, clause.Eq{Column: column, Value: value})
+		}
+		bounds[p.Field.Name] = b
+	}
+
+	errs = append(errs, checkFilterBounds(t, bounds)...)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return exprs, errs
+}
+
+// likeEscaper escapes the LIKE wildcards of a value with the escape character of Contains
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Contains matches the rows whose column contains Value as typed: % and _ are not wildcards.
+// The escape character is ! because a backslash needs different quoting on MySQL and SQLite.
+type Contains struct {
+	Column clause.Column
+	Value  string
+}
+
+// Build writes column LIKE ? ESCAPE '!'
+func (c Contains) Build(builder clause.Builder) {
+	builder.WriteQuoted(c.Column)
+	builder.WriteString(" LIKE ")
+	builder.AddVar(builder, "%"+likeEscaper.Replace(c.Value)+"%")
+	builder.WriteString(" ESCAPE '!'")
+}
+
+// coerceFilterValue parses raw as the type of f and reports whether a time had no clock part
+func coerceFilterValue(f *Field, raw string) (interface{}, bool, error) {
+	switch f.Kind() {
+	case KindID, KindInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("must be a whole number")
+		}
+		return n, false, nil
+	case KindTime:
+		for _, layout := range filterDateLayouts {
+			if tm, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+				return tm, layout == "2006-01-02", nil
+			}
+		}
+		return nil, false, fmt.Errorf("must be a date (YYYY-MM-DD) or date-time (YYYY-MM-DD HH:MM:SS)")
+	case KindEnum:
+		if allowed := f.EnumValues(); !util.Contains(raw, allowed) {
+			return nil, false, fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+		}
+	}
+	return raw, false, nil
+}
+
+func allowedFilterValues(f *Field) []string {
+	if f.Kind() == KindEnum {
+		return f.EnumValues()
+	}
+	return nil
+}
+
+// checkFilterBounds reports ranges whose lower bound is above the upper bound
+func checkFilterBounds(t *Table, bounds map[string][2]interface{}) FieldErrors {
+	var errs FieldErrors
+	for name, b := range bounds {
+		if b[0] == nil || b[1] == nil {
+			continue
+		}
+		var inverted bool
+		switch lo := b[0].(type) {
+		case int64:
+			inverted = lo > b[1].(int64)
+		case time.Time:
+			inverted = lo.After(b[1].(time.Time))
+		}
+		if inverted {
+			f := t.Field(name)
+			errs = append(errs, FieldError{Field: name, Key: f.Key, Alias: f.Alias, Value: []interface{}{b[0], b[1]}, Message: "minimum is greater than maximum"})
+		}
+	}
+	return errs
+}
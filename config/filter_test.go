@@ -0,0 +1,205 @@
+package config
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// filterTestOmnitags describes one table with a field of every filterable kind
+func filterTestOmnitags() (*Omnitags, *Table) {
+	c := loadTestOmnitags(environment(
+		"tabel_f3", "transaksi",
+		"tabel_f3_field1", "id",
+		"tabel_f3_field2", "harga",
+		"tabel_f3_field3", "tgl_transaksi",
+		"tabel_f3_field4", "metode",
+		"tabel_f3_field4_value1", "cash",
+		"tabel_f3_field4_value2", "transfer",
+		"tabel_f3_field5", "nama",
+		"tabel_f3_field6", "password",
+	))
+	return c, c.Schema.TableByName("transaksi")
+}
+
+func TestFilterParamsSkipSensitiveFields(t *testing.T) {
+	c, table := filterTestOmnitags()
+	for _, p := range c.FilterParams(table) {
+		if p.Field.Name == "password" {
+			t.Fatalf("FilterParams includes %s for the sensitive field password", p.Param)
+		}
+	}
+}
+
+func TestBuildFilter(t *testing.T) {
+	c, table := filterTestOmnitags()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		query  url.Values
+		want   []clause.Expression
+		errors []string // params or fields with an error
+	}{
+		{
+			name:  "min and max",
+			query: url.Values{"min_harga": {"10"}, "max_harga": {"20"}},
+			want: []clause.Expression{
+				clause.Gte{Column: clause.Column{Name: "harga"}, Value: int64(10)},
+				clause.Lte{Column: clause.Column{Name: "harga"}, Value: int64(20)},
+			},
+		},
+		{
+			name:  "date-only maximum includes the whole day",
+			query: url.Values{"max_tgl_transaksi": {"2024-05-01"}},
+			want:  []clause.Expression{clause.Lt{Column: clause.Column{Name: "tgl_transaksi"}, Value: day.AddDate(0, 0, 1)}},
+		},
+		{
+			name:  "date-time minimum",
+			query: url.Values{"min_tgl_transaksi": {"2024-05-01 08:30:00"}},
+			want:  []clause.Expression{clause.Gte{Column: clause.Column{Name: "tgl_transaksi"}, Value: day.Add(8*time.Hour + 30*time.Minute)}},
+		},
+		{
+			name:  "equal number and enum",
+			query: url.Values{"txt_harga": {"15"}, "txt_metode": {"cash"}},
+			want: []clause.Expression{
+				clause.Eq{Column: clause.Column{Name: "harga"}, Value: int64(15)},
+				clause.Eq{Column: clause.Column{Name: "metode"}, Value: "cash"},
+			},
+		},
+		{
+			name:  "text matches a substring",
+			query: url.Values{"txt_nama": {" 50%_off "}},
+			want:  []clause.Expression{Contains{Column: clause.Column{Name: "nama"}, Value: "50%_off"}},
+		},
+		{
+			name:  "empty values are ignored",
+			query: url.Values{"min_harga": {" "}, "txt_nama": {""}},
+		},
+		{
+			name:   "enum value outside the declared values",
+			query:  url.Values{"txt_metode": {"credit"}},
+			errors: []string{"txt_metode"},
+		},
+		{
+			name:   "number that is not whole",
+			query:  url.Values{"min_harga": {"1.5"}},
+			errors: []string{"min_harga"},
+		},
+		{
+			name:   "date in an unknown format",
+			query:  url.Values{"min_tgl_transaksi": {"01/05/2024"}},
+			errors: []string{"min_tgl_transaksi"},
+		},
+		{
+			name:   "inverted bounds",
+			query:  url.Values{"min_harga": {"20"}, "max_harga": {"10"}},
+			errors: []string{"harga"},
+		},
+		{
+			name:  "sensitive fields cannot be filtered",
+			query: url.Values{"txt_password": {"secret"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprs, errs := c.BuildFilter(table, tt.query)
+			if len(errs) != len(tt.errors) {
+				t.Fatalf("errors = %v, want %d", errs, len(tt.errors))
+			}
+			for i, fe := range errs {
+				if name := tt.errors[i]; fe.Param != name && (fe.Param != "" || fe.Field != name) {
+					t.Errorf("error %d is for %s/%s, want %s", i, fe.Param, fe.Field, name)
+				}
+			}
+			if len(tt.errors) > 0 {
+				return
+			}
+			if len(exprs) != len(tt.want) {
+				t.Fatalf("expressions = %#v, want %#v", exprs, tt.want)
+			}
+			for i := range exprs {
+				if !equalExpression(exprs[i], tt.want[i]) {
+					t.Errorf("expression %d = %#v, want %#v", i, exprs[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// equalExpression compares filter clauses, times by instant
+func equalExpression(got, want clause.Expression) bool {
+	value := func(e clause.Expression) (string, interface{}) {
+		switch e := e.(type) {
+		case clause.Gte:
+			return "gte " + e.Column.(clause.Column).Name, e.Value
+		case clause.Lte:
+			return "lte " + e.Column.(clause.Column).Name, e.Value
+		case clause.Lt:
+			return "lt " + e.Column.(clause.Column).Name, e.Value
+		case clause.Eq:
+			return "eq " + e.Column.(clause.Column).Name, e.Value
+		case Contains:
+			return "like " + e.Column.Name, e.Value
+		}
+		return "", nil
+	}
+	gotOp, gotValue := value(got)
+	wantOp, wantValue := value(want)
+	if gotOp == "" || gotOp != wantOp {
+		return false
+	}
+	if tm, ok := wantValue.(time.Time); ok {
+		g, ok := gotValue.(time.Time)
+		return ok && g.Equal(tm)
+	}
+	return gotValue == wantValue
+}
+
+func TestContainsMatchesWildcardsLiterally(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("CREATE TABLE produk (nama TEXT)").Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, nama := range []string{"diskon 50%", "diskon 500", "kode_a", "kodexa", "seru!", "seru"} {
+		if err := db.Exec("INSERT INTO produk (nama) VALUES (?)", nama).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"50%", []string{"diskon 50%"}},
+		{"%", []string{"diskon 50%"}},
+		{"_", []string{"kode_a"}},
+		{"!", []string{"seru!"}},
+		{"kode", []string{"kode_a", "kodexa"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		err := db.Table("produk").Where(Contains{Column: clause.Column{Name: "nama"}, Value: tt.value}).Order("nama").Pluck("nama", &got).Error
+		if err != nil {
+			t.Fatalf("%q: %v", tt.value, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q matches %v, want %v", tt.value, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q matches %v, want %v", tt.value, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// goFieldTypes maps an inferred field kind to the Go type used in generated models
+var goFieldTypes = map[FieldKind]string{
+	KindID:     "uint",
+	KindInt:    "int",
+	KindTime:   "*time.Time",
+	KindText:   "string",
+	KindString: "string",
+}
+
+// GenerateModels renders one Go source file per table group, keyed by file name.
+// The output only depends on the schema so regenerating an unchanged file yields identical sources.
+func GenerateModels(s *Schema, pkg string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, g := range s.TableGroups() {
+		var tables []*Table
+		for _, t := range g.Tables {
+			if t.Name != "" {
+				tables = append(tables, t)
+			}
+		}
+		if len(tables) == 0 {
+			continue
+		}
+
+		src, err := generateModelFile(pkg, tables)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", g.Name, err)
+		}
+		files[fmt.Sprintf("group_%s.go", g.Name)] = src
+	}
+	return files, nil
+}
+
+func generateModelFile(pkg string, tables []*Table) ([]byte, error) {
+	var body bytes.Buffer
+	usesTime := false
+
+	for _, t := range tables {
+		typeName := goName(t.Name)
+
+		// Enum types and constants
+		for _, f := range t.Fields {
+			if f.Kind() != KindEnum {
+				continue
+			}
+			enumType := typeName + goName(f.Name)
+			fmt.Fprintf(&body, "// %s holds the allowed values of %s.%s.\n", enumType, t.Name, f.Name)
+			fmt.Fprintf(&body, "type %s string\n\n", enumType)
+			body.WriteString("const (\n")
+			for _, v := range f.Values {
+				if v.Value == "" {
+					continue
+				}
+				if v.Alias != "" {
+					fmt.Fprintf(&body, "\t// %s\n", v.Alias)
+				}
+				fmt.Fprintf(&body, "\t%s%s %s = %q\n", enumType, goName(v.Value), enumType, v.Value)
+			}
+			body.WriteString(")\n\n")
+		}
+
+		// Struct
+		fmt.Fprintf(&body, "// %s maps the %s table (%s): %s.\n", typeName, t.Name, t.Key, commentText(t.Alias))
+		fmt.Fprintf(&body, "type %s struct {\n", typeName)
+		pk := t.PrimaryKey()
+		for _, f := range t.Fields {
+			if f.Name == "" {
+				continue
+			}
+			goType := goFieldTypes[f.Kind()]
+			if f.Kind() == KindEnum {
+				goType = typeName + goName(f.Name)
+			}
+			if f.Kind() == KindTime {
+				usesTime = true
+			}
+			gormTag := "column:" + f.Name
+			if f == pk {
+				gormTag += ";primaryKey"
+			}
+			if f.Kind() == KindText {
+				gormTag += ";type:text"
+			}
+			// Sensitive values such as password hashes never leave the server
+			jsonTag := f.Name
+			if f.Sensitive() {
+				jsonTag = "-"
+			}
+			if f.Alias != "" {
+				fmt.Fprintf(&body, "\t// %s\n", commentText(f.Alias))
+			}
+			fmt.Fprintf(&body, "\t%s %s `gorm:\"%s\" json:\"%s\"`\n", goName(f.Name), goType, gormTag, jsonTag)
+		}
+		body.WriteString("}\n\n")
+
+		fmt.Fprintf(&body, "// TableName returns the table name of %s.\n", typeName)
+		fmt.Fprintf(&body, "func (%s) TableName() string {\n\treturn %q\n}\n\n", typeName, t.Name)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by omnitags gen models. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	if usesTime {
+		out.WriteString("import \"time\"\n\n")
+	}
+	out.Write(body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+// commentText flattens a value so it can be placed on a single comment line
+func commentText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateModels(t *testing.T) {
+	s := ParseSchema(environment(
+		"tabel_c2", "users",
+		"tabel_c2_alias", "Users",
+		"tabel_c2_field1", "id",
+		"tabel_c2_field2", "email",
+		"tabel_c2_field3", "password",
+		"tabel_c2_field4", "api_token",
+		"tabel_c2_field5", "role",
+		"tabel_c2_field5_value1", "admin",
+		"tabel_c2_field5_value1_alias", "Administrator",
+	))
+	files, err := GenerateModels(s, "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(files["group_c.go"])
+	// gofmt aligns the struct columns
+	flat := strings.Join(strings.Fields(src), " ")
+	for _, want := range []string{
+		"package models",
+		"type Users struct {",
+		"ID uint `gorm:\"column:id;primaryKey\" json:\"id\"`",
+		"Email string `gorm:\"column:email\" json:\"email\"`",
+		"Password string `gorm:\"column:password\" json:\"-\"`",
+		"APIToken string `gorm:\"column:api_token\" json:\"-\"`",
+		"UsersRoleAdmin UsersRole = \"admin\"",
+		"return \"users\"",
+	} {
+		if !strings.Contains(flat, want) {
+			t.Errorf("group_c.go lacks %s\n%s", want, src)
+		}
+	}
+
+	again, err := GenerateModels(s, "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again["group_c.go"]) != src {
+		t.Error("regenerating an unchanged schema changes the output")
+	}
+}
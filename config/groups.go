@@ -0,0 +1,90 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GroupInfo is the optional metadata of a table group, e.g. tabel_b_alias=Website and tabel_b_order=2
+type GroupInfo struct {
+	Label string `json:"label,omitempty"`
+	// Order places the group in TableGroups, starting at 1; 0 means unordered
+	Order int `json:"order,omitempty"`
+}
+
+// Group is a table group with its tables in index order, e.g. b with tabel_b1 ... tabel_b12
+type Group struct {
+	Name   string   `json:"name"`
+	Label  string   `json:"label"`
+	Order  int      `json:"order,omitempty"`
+	Tables []*Table `json:"tables"`
+}
+
+// groupKeyPattern matches tabel_<group>_alias and tabel_<group>_order
+var groupKeyPattern = regexp.MustCompile(`^tabel_([a-z]+)_(alias|order)$`)
+
+// addGroupEntry records group metadata and reports whether key was one; invalid orders are left unparsed
+func (s *Schema) addGroupEntry(key, value string) bool {
+	m := groupKeyPattern.FindStringSubmatch(key)
+	if m == nil {
+		return false
+	}
+
+	info := s.GroupInfo[m[1]]
+	if m[2] == "alias" {
+		info.Label = value
+	} else {
+		order, err := strconv.Atoi(value)
+		if err != nil || order < 1 {
+			s.Unparsed = append(s.Unparsed, key)
+			return true
+		}
+		info.Order = order
+	}
+	s.GroupInfo[m[1]] = info
+	return true
+}
+
+// TableGroups returns the groups that have tables: ordered groups first by Order,
+// then the others by name. Groups without a label are labelled "Group <NAME>".
+func (s *Schema) TableGroups() []*Group {
+	var groups []*Group
+	byName := make(map[string]*Group)
+	for _, t := range s.Tables {
+		g, exists := byName[t.Group]
+		if !exists {
+			info := s.GroupInfo[t.Group]
+			g = &Group{Name: t.Group, Label: info.Label, Order: info.Order}
+			if g.Label == "" {
+				g.Label = "Group " + strings.ToUpper(t.Group)
+			}
+			byName[t.Group] = g
+			groups = append(groups, g)
+		}
+		g.Tables = append(g.Tables, t)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Order == 0) != (b.Order == 0) {
+			return a.Order != 0
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.Name < b.Name
+	})
+	return groups
+}
+
+// TableGroup returns the group with the given name, or nil when it has no tables
+func (s *Schema) TableGroup(name string) *Group {
+	for _, g := range s.TableGroups() {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTableGroups(t *testing.T) {
+	s := ParseSchema(environment(
+		"tabel_a_alias", "Master",
+		"tabel_f_alias", "Finance",
+		"tabel_f_order", "1",
+		"tabel_c_order", "2",
+		"tabel_e_order", "2",
+		"tabel_z_alias", "Nothing",
+		"tabel_z_order", "1",
+		"tabel_b12", "gallery",
+		"tabel_b2", "banner",
+		"tabel_a1", "users",
+		"tabel_d1", "logs",
+		"tabel_e1", "events",
+		"tabel_c1", "contacts",
+		"tabel_f3", "transaksi",
+	))
+
+	var got []string
+	for _, g := range s.TableGroups() {
+		var tables []string
+		for _, table := range g.Tables {
+			tables = append(tables, table.Code())
+		}
+		got = append(got, fmt.Sprintf("%s %q %d %v", g.Name, g.Label, g.Order, tables))
+	}
+	// Ordered groups first, equal orders and unordered groups by name; z has no tables
+	want := []string{
+		`f "Finance" 1 [f3]`,
+		`c "Group C" 2 [c1]`,
+		`e "Group E" 2 [e1]`,
+		`a "Master" 0 [a1]`,
+		`b "Group B" 0 [b2 b12]`,
+		`d "Group D" 0 [d1]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TableGroups =\n%v\nwant\n%v", got, want)
+	}
+	if got, want := s.Groups(), []string{"f", "c", "e", "a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Groups = %v, want %v", got, want)
+	}
+
+	if g := s.TableGroup("b"); g == nil || len(g.Tables) != 2 {
+		t.Errorf("TableGroup(b) = %+v, want the two b tables", g)
+	}
+	for _, name := range []string{"z", "x"} {
+		if g := s.TableGroup(name); g != nil {
+			t.Errorf("TableGroup(%s) = %+v, want nil for a group without tables", name, g)
+		}
+	}
+}
+
+func TestLoadDataTablesAndSections(t *testing.T) {
+	tables := []string{"tabel_b2", "banner", "tabel_f3", "transaksi"}
+	tests := []struct {
+		name     string
+		sections []string
+		want     int
+	}{
+		{"default", nil, defaultSections},
+		{"view_sections", []string{"view_sections", "3"}, 3},
+		{"no sections", []string{"view_sections", "0"}, 0},
+		{"invalid view_sections", []string{"view_sections", "many"}, defaultSections},
+		{"negative view_sections", []string{"view_sections", "-1"}, defaultSections},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := loadTestOmnitags(environment(append(append([]string{}, tables...), tt.sections...)...))
+
+			var codes []string
+			for code := range c.TL {
+				codes = append(codes, code)
+			}
+			if len(codes) != 3 || !containsString(codes, "ot") || !containsString(codes, "b2") || !containsString(codes, "f3") {
+				t.Errorf("TL = %v, want ot, b2 and f3", codes)
+			}
+
+			if len(c.V) != tt.want {
+				t.Fatalf("V has %d sections, want %d", len(c.V), tt.want)
+			}
+			for i := 1; i <= tt.want; i++ {
+				if want := fmt.Sprintf("contents/section_%d", i); c.V[i] != want {
+					t.Errorf("V[%d] = %q, want %q", i, c.V[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+// environment builds a parsed Postman environment from key/value pairs
+func environment(pairs ...string) map[string]interface{} {
+	values := make([]interface{}, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		values = append(values, map[string]interface{}{"key": pairs[i], "value": pairs[i+1], "type": "default", "enabled": true})
+	}
+	return map[string]interface{}{"name": "test", "values": values}
+}
+
+// withEntry appends an entry with the given type and enabled flag to data
+func withEntry(data map[string]interface{}, key, value, typ string, enabled bool) map[string]interface{} {
+	data["values"] = append(data["values"].([]interface{}), map[string]interface{}{"key": key, "value": value, "type": typ, "enabled": enabled})
+	return data
+}
+
+// loadTestOmnitags loads data with the default naming convention
+func loadTestOmnitags(data map[string]interface{}) *Omnitags {
+	c := NewConfig()
+	c.LoadData(data)
+	return c
+}
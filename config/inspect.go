@@ -0,0 +1,65 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// DerivedValue is an entry of a derived map together with the environment entry it was derived from
+type DerivedValue struct {
+	Map         string `json:"map"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Source      string `json:"source,omitempty"`
+	SourceValue string `json:"source_value,omitempty"`
+}
+
+// derivedMaps returns the maps filled by the naming convention, by the names rules use
+func (c *Omnitags) derivedMaps() map[string]map[string]string {
+	return map[string]map[string]string{
+		"vinput":      c.VInput,
+		"vpost":       c.VPost,
+		"vget":        c.VGet,
+		"flash1msg":   c.Flash1Msg,
+		"flash":       c.Flash,
+		"flashfunc":   c.FlashFunc,
+		"flashmsg":    c.FlashMsg,
+		"vuploadpath": c.VUploadPath,
+		"views":       c.Views,
+		"titles":      c.Titles,
+	}
+}
+
+// Derived lists the derived values whose key or source key starts with prefix, sorted by map and key.
+// An empty mapName matches every map. Values built into NewConfig have no source.
+func (c *Omnitags) Derived(mapName, prefix string) []DerivedValue {
+	var out []DerivedValue
+	for name, values := range c.derivedMaps() {
+		if mapName != "" && name != mapName {
+			continue
+		}
+		for key, value := range values {
+			source := c.DerivedFrom[name][key]
+			if !strings.HasPrefix(key, prefix) && (source == "" || !strings.HasPrefix(source, prefix)) {
+				continue
+			}
+			d := DerivedValue{Map: name, Key: key, Value: value, Source: source}
+			if source != "" {
+				d.SourceValue = c.Aliases[source]
+			}
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Map != out[j].Map {
+			return out[i].Map < out[j].Map
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// DerivedMapNames lists the maps Derived can filter on
+func DerivedMapNames() []string {
+	return append([]string(nil), namingMaps...)
+}
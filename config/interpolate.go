@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// variablePattern matches Postman style references: {{name}} or {{name|default}}
+var variablePattern = regexp.MustCompile(`\{\{\s*([^{}|]+?)\s*(?:\|([^{}]*))?\}\}`)
+
+// lookupEnv resolves references that are not keys of the environment file
+var lookupEnv = os.LookupEnv
+
+// envVariablePrefix marks the OS environment variables non-secret values may reference, e.g. {{OMNITAGS_BASEURL}}.
+// Other variables such as DBPASS are only available to secret entries, so they never reach Aliases.
+const envVariablePrefix = "OMNITAGS_"
+
+// VariableIssue describes a {{reference}} that could not be expanded
+type VariableIssue struct {
+	Key       string `json:"key"`
+	Reference string `json:"reference"`
+	Problem   string `json:"problem"`
+}
+
+func (i VariableIssue) Error() string {
+	return fmt.Sprintf("%s: {{%s}} %s", i.Key, i.Reference, i.Problem)
+}
+
+// interpolator expands references between entries, remembering finished keys
+type interpolator struct {
+	entries  map[string]environmentEntry
+	state    map[string]int // 0 unvisited, 1 resolving, 2 done
+	resolved map[string]string
+	issues   []VariableIssue
+	reported map[string]bool
+}
+
+// resolvedEntries returns the enabled entries with {{references}} expanded against other keys,
+// then OS environment variables (OMNITAGS_* ones for non-secret values), then the inline default.
+// Postman dynamic variables such as {{$guid}} are left untouched.
+// A non-secret value may not pull in a secret, such references stay unresolved.
+func resolvedEntries(data map[string]interface{}) ([]environmentEntry, []VariableIssue) {
+	all := environmentEntries(data)
+	in := &interpolator{
+		entries:  make(map[string]environmentEntry),
+		state:    make(map[string]int),
+		resolved: make(map[string]string),
+		reported: make(map[string]bool),
+	}
+	for _, entry := range all {
+		if entry.Enabled {
+			in.entries[entry.Key] = entry
+		}
+	}
+
+	out := make([]environmentEntry, 0, len(all))
+	for _, entry := range all {
+		if entry.Enabled && strings.Contains(entry.Value, "{{") {
+			// Duplicate keys resolve to the last value, matching how they are loaded
+			entry.Value = in.resolve(entry.Key)
+		}
+		out = append(out, entry)
+	}
+	return out, in.issues
+}
+
+// resolve returns the fully expanded value of key
+func (in *interpolator) resolve(key string) string {
+	if in.state[key] == 2 {
+		return in.resolved[key]
+	}
+	in.state[key] = 1
+	value := in.expand(in.entries[key])
+	in.state[key] = 2
+	in.resolved[key] = value
+	return value
+}
+
+// expand substitutes every reference inside the value of entry
+func (in *interpolator) expand(entry environmentEntry) string {
+	return variablePattern.ReplaceAllStringFunc(entry.Value, func(match string) string {
+		m := variablePattern.FindStringSubmatch(match)
+		name, fallback, hasFallback := m[1], m[2], strings.Contains(match, "|")
+		if strings.HasPrefix(name, "$") {
+			return match
+		}
+
+		if ref, exists := in.entries[name]; exists {
+			switch {
+			case ref.Secret() && !entry.Secret():
+				in.report(entry.Key, name, "references a secret from a non-secret value")
+				return match
+			case in.state[name] == 1:
+				in.report(entry.Key, name, "is part of a reference cycle")
+				return match
+			}
+			return in.resolve(name)
+		}
+		if entry.Secret() || strings.HasPrefix(name, envVariablePrefix) {
+			if value, exists := lookupEnv(name); exists {
+				return value
+			}
+		}
+		if hasFallback {
+			return fallback
+		}
+		if _, exists := lookupEnv(name); exists {
+			in.report(entry.Key, name, "is an environment variable without the "+envVariablePrefix+" prefix, only secret values may reference it")
+			return match
+		}
+		in.report(entry.Key, name, "is not defined in the file or the environment")
+		return match
+	})
+}
+
+func (in *interpolator) report(key, reference, problem string) {
+	id := key + "\x00" + reference
+	if in.reported[id] {
+		return
+	}
+	in.reported[id] = true
+	in.issues = append(in.issues, VariableIssue{Key: key, Reference: reference, Problem: problem})
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolvedEntries(t *testing.T) {
+	env := map[string]string{"OMNITAGS_HOST": "example.com", "DBPASS": "hunter2"}
+	defer func(original func(string) (string, bool)) { lookupEnv = original }(lookupEnv)
+	lookupEnv = func(name string) (string, bool) {
+		value, exists := env[name]
+		return value, exists
+	}
+
+	tests := []struct {
+		name   string
+		data   map[string]interface{}
+		key    string
+		want   string
+		issues []string
+	}{
+		{
+			name: "file key",
+			data: environment("base_url", "https://example.com", "api_url", "{{base_url}}/api"),
+			key:  "api_url",
+			want: "https://example.com/api",
+		},
+		{
+			name: "nested keys",
+			data: environment("a", "{{b}}-a", "b", "{{c}}-b", "c", "c"),
+			key:  "a",
+			want: "c-b-a",
+		},
+		{
+			name: "prefixed environment variable",
+			data: environment("url", "https://{{OMNITAGS_HOST}}"),
+			key:  "url",
+			want: "https://example.com",
+		},
+		{
+			name:   "unprefixed environment variable in a plain value",
+			data:   environment("leak", "{{DBPASS}}"),
+			key:    "leak",
+			want:   "{{DBPASS}}",
+			issues: []string{"leak: {{DBPASS}} is an environment variable without the OMNITAGS_ prefix"},
+		},
+		{
+			name: "unprefixed environment variable falls back to the default",
+			data: environment("leak", "{{DBPASS|none}}"),
+			key:  "leak",
+			want: "none",
+		},
+		{
+			name: "unprefixed environment variable in a secret",
+			data: withEntry(environment(), "db_password", "{{DBPASS}}", "secret", true),
+			key:  "db_password",
+			want: "hunter2",
+		},
+		{
+			name: "default",
+			data: environment("title", "{{site_name|Omnitags}} admin"),
+			key:  "title",
+			want: "Omnitags admin",
+		},
+		{
+			name: "empty default",
+			data: environment("title", "{{site_name|}}admin"),
+			key:  "title",
+			want: "admin",
+		},
+		{
+			name:   "undefined",
+			data:   environment("title", "{{site_name}}"),
+			key:    "title",
+			want:   "{{site_name}}",
+			issues: []string{"title: {{site_name}} is not defined"},
+		},
+		{
+			name:   "cycle",
+			data:   environment("a", "{{b}}", "b", "{{a}}"),
+			key:    "a",
+			want:   "{{a}}",
+			issues: []string{"b: {{a}} is part of a reference cycle"},
+		},
+		{
+			name:   "self reference",
+			data:   environment("a", "x{{a}}"),
+			key:    "a",
+			want:   "x{{a}}",
+			issues: []string{"a: {{a}} is part of a reference cycle"},
+		},
+		{
+			name:   "secret from a plain value",
+			data:   withEntry(environment("dsn", "mysql://root:{{db_password}}@db"), "db_password", "hunter2", "secret", true),
+			key:    "dsn",
+			want:   "mysql://root:{{db_password}}@db",
+			issues: []string{"dsn: {{db_password}} references a secret from a non-secret value"},
+		},
+		{
+			name: "secret from a secret",
+			data: withEntry(withEntry(environment(), "db_password", "hunter2", "secret", true), "dsn", "root:{{db_password}}", "secret", true),
+			key:  "dsn",
+			want: "root:hunter2",
+		},
+		{
+			name:   "disabled key",
+			data:   withEntry(environment("url", "{{host}}/api"), "host", "example.com", "default", false),
+			key:    "url",
+			want:   "{{host}}/api",
+			issues: []string{"url: {{host}} is not defined"},
+		},
+		{
+			name: "postman dynamic variable",
+			data: environment("id", "{{$guid}}"),
+			key:  "id",
+			want: "{{$guid}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, issues := resolvedEntries(tt.data)
+			var got string
+			for _, entry := range entries {
+				if entry.Key == tt.key {
+					got = entry.Value
+				}
+			}
+			if got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+			}
+			if len(issues) != len(tt.issues) {
+				t.Fatalf("issues = %v, want %d", issues, len(tt.issues))
+			}
+			for i, issue := range issues {
+				if !strings.HasPrefix(issue.Error(), tt.issues[i]) {
+					t.Errorf("issue %d = %q, want prefix %q", i, issue.Error(), tt.issues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolatedSecretsStayOutOfAliases(t *testing.T) {
+	defer func(original func(string) (string, bool)) { lookupEnv = original }(lookupEnv)
+	lookupEnv = func(name string) (string, bool) { return "hunter2", name == "JWTSECRET" }
+
+	c := loadTestOmnitags(withEntry(environment("token", "{{JWTSECRET}}"), "jwt", "{{JWTSECRET}}", "secret", true))
+	if value := c.Aliases["token"]; value == "hunter2" {
+		t.Errorf("Aliases[token] = %q, the process secret leaked", value)
+	}
+	if value, _ := c.Secrets.Get("jwt"); value != "hunter2" {
+		t.Errorf("Secrets[jwt] = %q, want the environment value", value)
+	}
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DatabaseMapping records which table or column an introspected environment key describes
+type DatabaseMapping struct {
+	Key    string `json:"key"`
+	Table  string `json:"table"`
+	Column string `json:"column,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Alias  string `json:"alias"`
+	// Placeholder is set when the alias was made up from the name and still needs a human touch
+	Placeholder bool   `json:"placeholder"`
+	Note        string `json:"note,omitempty"`
+}
+
+// enumTypePattern matches MySQL enum column types, e.g. enum('aktif','nonaktif')
+var enumTypePattern = regexp.MustCompile(`(?i)^enum\((.*)\)$`)
+
+// sqliteCheckPattern matches CHECK ("column" IN ('a','b')) constraints as written by GenerateDDL
+var sqliteCheckPattern = regexp.MustCompile("(?i)CHECK\\s*\\(\\s*[\"`\\[]?(\\w+)[\"`\\]]?\\s+IN\\s*\\(([^)]*)\\)\\s*\\)")
+
+// quotedValuePattern matches single-quoted SQL string literals
+var quotedValuePattern = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// IntrospectDatabase reads the catalog of db into a schema whose tables are numbered
+// within group, e.g. tabel_a1, tabel_a2, in alphabetical order of their names.
+// Column comments become field aliases, other aliases are placeholders derived from the names.
+func IntrospectDatabase(db *gorm.DB, group string) (*Schema, []DatabaseMapping, error) {
+	if !regexp.MustCompile(`^[a-z]+$`).MatchString(group) {
+		return nil, nil, fmt.Errorf("table group %q must be lower-case letters", group)
+	}
+
+	migrator := db.Migrator()
+	names, err := migrator.GetTables()
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing tables: %w", err)
+	}
+	sort.Strings(names)
+
+	s := NewSchema()
+	if db.Dialector.Name() != "sqlite" {
+		// SQLite only knows the "main" catalog, the file name is a better environment name
+		s.Name = migrator.CurrentDatabase()
+		s.Settings["database"] = s.Name
+	}
+
+	var checks map[string][]string
+	var mappings []DatabaseMapping
+	for _, name := range names {
+		if strings.HasPrefix(name, "sqlite_") {
+			continue
+		}
+		columns, err := migrator.ColumnTypes(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading columns of %s: %w", name, err)
+		}
+		if db.Dialector.Name() == "sqlite" {
+			if checks, err = sqliteEnumChecks(db, name); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		t := &Table{Group: group, Index: len(s.Tables) + 1, Name: name, Alias: placeholderAlias(name), Fields: []*Field{}}
+		t.Key = "tabel_" + t.Code()
+		s.Tables = append(s.Tables, t)
+		mappings = append(mappings, DatabaseMapping{Key: t.Key, Table: name, Alias: t.Alias, Placeholder: true})
+
+		for i, column := range columns {
+			f := &Field{Key: fmt.Sprintf("%s_field%d", t.Key, i+1), Index: i + 1, Name: column.Name()}
+			m := DatabaseMapping{Key: f.Key, Table: name, Column: f.Name, Type: columnTypeName(column)}
+
+			if comment, ok := column.Comment(); ok && strings.TrimSpace(comment) != "" {
+				f.Alias = strings.TrimSpace(comment)
+			} else {
+				f.Alias, m.Placeholder = placeholderAlias(f.Name), true
+			}
+			m.Alias = f.Alias
+
+			values := enumTypeValues(m.Type)
+			if values == nil {
+				values = checks[f.Name]
+			}
+			for k, value := range values {
+				f.Values = append(f.Values, EnumValue{Key: fmt.Sprintf("%s_value%d", f.Key, k+1), Index: k + 1, Value: value, Alias: placeholderAlias(value)})
+			}
+			if len(values) > 0 {
+				m.Note = fmt.Sprintf("%d enum values", len(values))
+			}
+			if pk, ok := column.PrimaryKey(); ok && pk && f.Name != "id" {
+				m.Note = strings.TrimPrefix(m.Note+", primary key", ", ")
+			}
+
+			t.Fields = append(t.Fields, f)
+			mappings = append(mappings, m)
+		}
+		if len(columns) > 0 && t.Field("id") == nil {
+			mappings[len(mappings)-len(columns)-1].Note = "no id column, the first column is used as primary key"
+		}
+	}
+	return s, mappings, nil
+}
+
+// columnTypeName returns the full column type when the driver knows it, e.g. varchar(255)
+func columnTypeName(column gorm.ColumnType) string {
+	if full, ok := column.ColumnType(); ok && full != "" {
+		return full
+	}
+	return strings.ToLower(column.DatabaseTypeName())
+}
+
+// enumTypeValues returns the values of a MySQL enum column type, or nil
+func enumTypeValues(columnType string) []string {
+	m := enumTypePattern.FindStringSubmatch(strings.TrimSpace(columnType))
+	if m == nil {
+		return nil
+	}
+	return quotedValues(m[1])
+}
+
+// sqliteEnumChecks returns the allowed values of every column constrained by CHECK (column IN (...))
+func sqliteEnumChecks(db *gorm.DB, table string) (map[string][]string, error) {
+	var ddl string
+	if err := db.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&ddl).Error; err != nil {
+		return nil, fmt.Errorf("reading definition of %s: %w", table, err)
+	}
+	checks := make(map[string][]string)
+	for _, m := range sqliteCheckPattern.FindAllStringSubmatch(ddl, -1) {
+		checks[m[1]] = quotedValues(m[2])
+	}
+	return checks, nil
+}
+
+// quotedValues unquotes a comma separated list of SQL string literals
+func quotedValues(list string) []string {
+	var values []string
+	for _, m := range quotedValuePattern.FindAllStringSubmatch(list, -1) {
+		values = append(values, strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return values
+}
+
+// placeholderAlias turns a snake_case name into a readable label, e.g. id_user becomes "ID User"
+func placeholderAlias(name string) string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		lower := strings.ToLower(part)
+		if commonInitialisms[lower] {
+			words = append(words, strings.ToUpper(lower))
+			continue
+		}
+		words = append(words, strings.ToUpper(lower[:1])+lower[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+// postmanEnvironment is the layout of an exported Postman environment file
+type postmanEnvironment struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	Values     []environmentEntry `json:"values"`
+	Scope      string             `json:"_postman_variable_scope"`
+	ExportedAt string             `json:"_postman_exported_at"`
+}
+
+// environmentValues returns the key-value pairs describing the schema, settings first, in the order of the shipped file
+func (s *Schema) environmentValues() []environmentEntry {
+	var entries []environmentEntry
+	add := func(key, value string) {
+		entries = append(entries, environmentEntry{Key: key, Value: value, Type: "default", Enabled: true})
+	}
+
+	settings := make([]string, 0, len(s.Settings))
+	for key := range s.Settings {
+		settings = append(settings, key)
+	}
+	sort.Strings(settings)
+	for _, key := range settings {
+		add(key, s.Settings[key])
+	}
+
+	groups := make([]string, 0, len(s.GroupInfo))
+	for name := range s.GroupInfo {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	for _, name := range groups {
+		if info := s.GroupInfo[name]; info.Label != "" {
+			add("tabel_"+name+"_alias", info.Label)
+		}
+		if info := s.GroupInfo[name]; info.Order != 0 {
+			add("tabel_"+name+"_order", strconv.Itoa(info.Order))
+		}
+	}
+
+	for _, t := range s.Tables {
+		add(t.Key, t.Name)
+		add(t.Key+"_alias", t.Alias)
+		if t.Alias2 != "" {
+			add(t.Key+"_alias2", t.Alias2)
+		}
+		for _, f := range t.Fields {
+			add(f.Key, f.Name)
+			add(f.Key+"_alias", f.Alias)
+			if f.Ref != "" {
+				add(f.Key+"_ref", f.Ref)
+			}
+			for _, v := range f.Values {
+				add(v.Key, v.Value)
+				add(v.Key+"_alias", v.Alias)
+			}
+		}
+	}
+	return entries
+}
+
+// MarshalEnvironment renders the schema as a Postman environment file
+func (s *Schema) MarshalEnvironment() ([]byte, error) {
+	env := postmanEnvironment{
+		ID:         newUUID(),
+		Name:       s.Name,
+		Values:     s.environmentValues(),
+		Scope:      "environment",
+		ExportedAt: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	return json.MarshalIndent(env, "", "\t")
+}
+
+// newUUID returns a random version 4 UUID, as Postman uses for environment ids
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestIntrospectDatabase(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE "users" ("id" INTEGER PRIMARY KEY AUTOINCREMENT, "email" TEXT NULL, "status" TEXT CHECK ("status" IN ('aktif','nonaktif')) NULL)`,
+		`CREATE TABLE "api_tokens" ("token" TEXT PRIMARY KEY, "id_user" INTEGER NULL)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, _, err := IntrospectDatabase(db, "A1"); err == nil {
+		t.Error("IntrospectDatabase accepts the group A1")
+	}
+	s, mappings, err := IntrospectDatabase(db, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, table := range s.Tables {
+		names = append(names, table.Key+"="+table.Name)
+	}
+	if want := []string{"tabel_a1=api_tokens", "tabel_a2=users"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("tables = %v, want %v", names, want)
+	}
+	if got := s.Table("a2").Field("status").EnumValues(); !reflect.DeepEqual(got, []string{"aktif", "nonaktif"}) {
+		t.Errorf("status values = %v, want the CHECK values", got)
+	}
+	if got := s.Table("a1").Field("id_user").Alias; got != "ID User" {
+		t.Errorf("id_user alias = %q, want the placeholder ID User", got)
+	}
+
+	notes := map[string]string{}
+	for _, m := range mappings {
+		notes[m.Key] = m.Note
+	}
+	if notes["tabel_a1"] != "no id column, the first column is used as primary key" || notes["tabel_a1_field1"] != "primary key" || notes["tabel_a2_field3"] != "2 enum values" {
+		t.Errorf("notes = %v", notes)
+	}
+}
+
+func TestMarshalEnvironmentRoundTrip(t *testing.T) {
+	from := ParseSchema(environment(
+		"base_url", "http://localhost",
+		"tabel_f_alias", "Finance",
+		"tabel_f_order", "2",
+		"tabel_f3", "transaksi",
+		"tabel_f3_alias", "Transaksi",
+		"tabel_f3_alias2", "Transactions",
+		"tabel_f3_field1", "id",
+		"tabel_f3_field1_alias", "ID",
+		"tabel_f3_field2", "pembeli",
+		"tabel_f3_field2_alias", "Pembeli",
+		"tabel_f3_field2_ref", "users.id",
+		"tabel_f3_field3", "metode",
+		"tabel_f3_field3_alias", "Metode",
+		"tabel_f3_field3_value1", "cash",
+		"tabel_f3_field3_value1_alias", "Tunai",
+	))
+	data, err := from.MarshalEnvironment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env map[string]interface{}
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	to := ParseSchema(env)
+
+	if diff := DiffSchemas(from, to); !diff.Empty() {
+		t.Errorf("round trip changes %+v\n%s", diff.Changes, data)
+	}
+	if got := to.Table("f3").Field("pembeli").Ref; got != "users.id" {
+		t.Errorf("pembeli ref = %q, want users.id", got)
+	}
+	if got := to.GroupInfo["f"]; got.Label != "Finance" || got.Order != 2 {
+		t.Errorf("group f = %+v, want Finance ordered 2", got)
+	}
+}
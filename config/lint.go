@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity ranks lint issues
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// severityRank orders severities from least to most severe
+var severityRank = map[Severity]int{SeverityInfo: 0, SeverityWarning: 1, SeverityError: 2}
+
+// AtLeast reports whether s is as severe as other
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRank[s] >= severityRank[other]
+}
+
+// ParseSeverity validates a severity name
+func ParseSeverity(name string) (Severity, error) {
+	if _, ok := severityRank[Severity(name)]; ok {
+		return Severity(name), nil
+	}
+	return "", fmt.Errorf("unknown severity %q, expected error, warning or info", name)
+}
+
+// LintIssue is a single problem found in an environment file
+type LintIssue struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Key      string   `json:"key"`
+	Message  string   `json:"message"`
+}
+
+// LintReport is the machine-readable result of linting an environment file
+type LintReport struct {
+	Source   string      `json:"source,omitempty"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+	Infos    int         `json:"infos"`
+	Issues   []LintIssue `json:"issues"`
+}
+
+// NewLintReport counts the issues per severity
+func NewLintReport(source string, issues []LintIssue) LintReport {
+	r := LintReport{Source: source, Issues: issues}
+	if r.Issues == nil {
+		r.Issues = []LintIssue{}
+	}
+	for _, issue := range issues {
+		switch issue.Severity {
+		case SeverityError:
+			r.Errors++
+		case SeverityWarning:
+			r.Warnings++
+		default:
+			r.Infos++
+		}
+	}
+	return r
+}
+
+// Fails reports whether the report holds an issue at or above the threshold
+func (r LintReport) Fails(threshold Severity) bool {
+	for _, issue := range r.Issues {
+		if issue.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+type linter struct {
+	issues []LintIssue
+}
+
+func (l *linter) add(severity Severity, rule, key, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{Severity: severity, Rule: rule, Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// Lint reports every structural problem of the schema
+func (s *Schema) Lint() []LintIssue {
+	l := &linter{}
+
+	for _, key := range s.DuplicateKeys {
+		l.add(SeverityError, "duplicate-key", key, "key %s is defined more than once, the last value wins", key)
+	}
+	for _, issue := range s.VariableIssues {
+		l.add(SeverityError, "unresolved-variable", issue.Key, "%s", issue.Error())
+	}
+	for _, key := range s.Unparsed {
+		l.add(SeverityError, "unknown-key", key, "key %s does not follow the tabel_<group><n>[_fieldN[_valueM]][_alias], tabel_<group><n>_fieldN_ref or tabel_<group>_alias|_order convention", key)
+	}
+
+	tableNames := make(map[string]string)
+	for _, t := range s.Tables {
+		switch {
+		case t.Name == "":
+			l.add(SeverityError, "missing-table-name", t.Key, "%s has aliases or fields but no table name", t.Key)
+		case tableNames[t.Name] != "":
+			l.add(SeverityError, "duplicate-table", t.Key, "%s reuses table name %q of %s", t.Key, t.Name, tableNames[t.Name])
+		default:
+			tableNames[t.Name] = t.Key
+		}
+		if t.Alias == "" {
+			l.add(SeverityWarning, "missing-alias", t.Key+"_alias", "%s has no alias", t.Key)
+		}
+		if len(t.Fields) == 0 {
+			l.add(SeverityWarning, "no-fields", t.Key, "%s (%s) declares no fields", t.Key, t.Name)
+		}
+		lintFields(l, t)
+	}
+	_, relationIssues := s.resolveRelations()
+	l.issues = append(l.issues, relationIssues...)
+
+	return l.issues
+}
+
+func lintFields(l *linter, t *Table) {
+	fieldNames := make(map[string]string)
+	expected := 1
+	for _, f := range t.Fields {
+		if f.Index != expected {
+			l.add(SeverityWarning, "field-gap", f.Key, "%s follows field%d, numbering has a gap", f.Key, expected-1)
+		}
+		expected = f.Index + 1
+
+		switch {
+		case f.Name == "":
+			l.add(SeverityError, "missing-field-name", f.Key, "%s has an alias or values but no field name", f.Key)
+		case fieldNames[f.Name] != "":
+			l.add(SeverityError, "duplicate-field", f.Key, "%s reuses field name %q of %s", f.Key, f.Name, fieldNames[f.Name])
+		default:
+			fieldNames[f.Name] = f.Key
+		}
+		if f.Alias == "" {
+			l.add(SeverityWarning, "missing-alias", f.Key+"_alias", "%s has no alias", f.Key)
+		}
+
+		expectedValue := 1
+		for _, v := range f.Values {
+			if v.Index != expectedValue {
+				l.add(SeverityWarning, "value-gap", v.Key, "%s follows value%d, numbering has a gap", v.Key, expectedValue-1)
+			}
+			expectedValue = v.Index + 1
+
+			if v.Value == "" {
+				l.add(SeverityWarning, "empty-value", v.Key, "%s is empty and will never be accepted", v.Key)
+			} else if v.Alias == "" {
+				l.add(SeverityInfo, "missing-value-alias", v.Key+"_alias", "%s (%s) has no alias", v.Key, v.Value)
+			}
+		}
+	}
+}
+
+// Lint reports problems of the schema and of the table groups
+func (c *Omnitags) Lint() []LintIssue {
+	issues := c.Schema.Lint()
+	l := &linter{issues: issues}
+
+	var names []string
+	for name := range c.Schema.GroupInfo {
+		if c.Schema.TableGroup(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		l.add(SeverityWarning, "group-unknown", "tabel_"+name, "group %q has a label or order but no tabel_%s<n> tables", name, name)
+	}
+
+	orders := make(map[int]string)
+	for _, g := range c.Schema.TableGroups() {
+		if g.Order == 0 {
+			continue
+		}
+		if other, taken := orders[g.Order]; taken {
+			l.add(SeverityInfo, "group-order", "tabel_"+g.Name+"_order", "groups %q and %q share order %d, they are sorted by name", other, g.Name, g.Order)
+		}
+		orders[g.Order] = g.Name
+	}
+
+	return l.issues
+}
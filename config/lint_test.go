@@ -0,0 +1,111 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// lintBase is an environment file without lint issues
+var lintBase = []string{
+	"tabel_f_alias", "Finance",
+	"tabel_f_order", "1",
+	"tabel_f3", "transaksi",
+	"tabel_f3_alias", "Transaksi",
+	"tabel_f3_field1", "id",
+	"tabel_f3_field1_alias", "ID",
+	"tabel_f3_field2", "metode",
+	"tabel_f3_field2_alias", "Metode",
+	"tabel_f3_field2_value1", "cash",
+	"tabel_f3_field2_value1_alias", "Tunai",
+}
+
+func TestLint(t *testing.T) {
+	type issue struct {
+		Severity Severity
+		Rule     string
+		Key      string
+	}
+	tests := []struct {
+		name  string
+		extra []string
+		want  []issue
+	}{
+		{"clean", nil, nil},
+		{"duplicate key", []string{"tabel_f3_alias", "Transaksi"}, []issue{{SeverityError, "duplicate-key", "tabel_f3_alias"}}},
+		{"unresolved variable", []string{"base_url", "{{host}}"}, []issue{{SeverityError, "unresolved-variable", "base_url"}}},
+		{"unknown key", []string{"tabel_f3_label", "x"}, []issue{{SeverityError, "unknown-key", "tabel_f3_label"}}},
+		{"missing table name", []string{"tabel_f4_alias", "Pelanggan"}, []issue{
+			{SeverityError, "missing-table-name", "tabel_f4"},
+			{SeverityWarning, "no-fields", "tabel_f4"},
+		}},
+		{"duplicate table", []string{"tabel_f4", "transaksi", "tabel_f4_alias", "Lagi", "tabel_f4_field1", "id", "tabel_f4_field1_alias", "ID"}, []issue{
+			{SeverityError, "duplicate-table", "tabel_f4"},
+		}},
+		{"missing table alias", []string{"tabel_f4", "pelanggan", "tabel_f4_field1", "id", "tabel_f4_field1_alias", "ID"}, []issue{
+			{SeverityWarning, "missing-alias", "tabel_f4_alias"},
+		}},
+		{"field gap", []string{"tabel_f3_field4", "harga", "tabel_f3_field4_alias", "Harga"}, []issue{{SeverityWarning, "field-gap", "tabel_f3_field4"}}},
+		{"missing field name", []string{"tabel_f3_field3_alias", "Harga"}, []issue{{SeverityError, "missing-field-name", "tabel_f3_field3"}}},
+		{"duplicate field", []string{"tabel_f3_field3", "metode", "tabel_f3_field3_alias", "Metode"}, []issue{{SeverityError, "duplicate-field", "tabel_f3_field3"}}},
+		{"missing field alias", []string{"tabel_f3_field3", "harga"}, []issue{{SeverityWarning, "missing-alias", "tabel_f3_field3_alias"}}},
+		{"value gap", []string{"tabel_f3_field2_value3", "qris", "tabel_f3_field2_value3_alias", "QRIS"}, []issue{{SeverityWarning, "value-gap", "tabel_f3_field2_value3"}}},
+		{"empty value", []string{"tabel_f3_field2_value2", ""}, []issue{{SeverityWarning, "empty-value", "tabel_f3_field2_value2"}}},
+		{"missing value alias", []string{"tabel_f3_field2_value2", "transfer"}, []issue{{SeverityInfo, "missing-value-alias", "tabel_f3_field2_value2_alias"}}},
+		{"unknown group", []string{"tabel_z_alias", "Nothing"}, []issue{{SeverityWarning, "group-unknown", "tabel_z"}}},
+		{"shared group order", []string{"tabel_g_order", "1", "tabel_g1", "log", "tabel_g1_alias", "Log", "tabel_g1_field1", "id", "tabel_g1_field1_alias", "ID"}, []issue{
+			{SeverityInfo, "group-order", "tabel_g_order"},
+		}},
+		{"unresolved relation", []string{"tabel_f3_field3", "id_cabang", "tabel_f3_field3_alias", "Cabang"}, []issue{
+			{SeverityInfo, "relation-unresolved", "tabel_f3_field3_ref"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := loadTestOmnitags(environment(append(append([]string{}, lintBase...), tt.extra...)...))
+			var got []issue
+			for _, i := range c.Lint() {
+				got = append(got, issue{i.Severity, i.Rule, i.Key})
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].Key+got[i].Rule < got[j].Key+got[j].Rule })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintReport(t *testing.T) {
+	issues := []LintIssue{
+		{Severity: SeverityError, Rule: "duplicate-key"},
+		{Severity: SeverityWarning, Rule: "missing-alias"},
+		{Severity: SeverityWarning, Rule: "field-gap"},
+		{Severity: SeverityInfo, Rule: "missing-value-alias"},
+	}
+	r := NewLintReport("test", issues)
+	if r.Errors != 1 || r.Warnings != 2 || r.Infos != 1 {
+		t.Errorf("counts = %d/%d/%d, want 1/2/1", r.Errors, r.Warnings, r.Infos)
+	}
+
+	tests := []struct {
+		issues    []LintIssue
+		threshold Severity
+		want      bool
+	}{
+		{issues, SeverityError, true},
+		{issues[1:], SeverityError, false},
+		{issues[1:], SeverityWarning, true},
+		{issues[3:], SeverityWarning, false},
+		{issues[3:], SeverityInfo, true},
+		{nil, SeverityInfo, false},
+	}
+	for _, tt := range tests {
+		if got := NewLintReport("", tt.issues).Fails(tt.threshold); got != tt.want {
+			t.Errorf("Fails(%s) with %d issues = %v, want %v", tt.threshold, len(tt.issues), got, tt.want)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity accepts fatal")
+	}
+}
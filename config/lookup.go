@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrUnknownKey is matched by every UnknownKeyError
+var ErrUnknownKey = errors.New("unknown omnitags key")
+
+// UnknownKeyError reports a key missing from one of the Omnitags maps, with the closest existing key
+type UnknownKeyError struct {
+	Map        string
+	Key        string
+	Suggestion string
+}
+
+func (e *UnknownKeyError) Error() string {
+	msg := fmt.Sprintf("unknown omnitags key %q in %s", e.Key, e.Map)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", e.Suggestion)
+	}
+	return msg
+}
+
+func (e *UnknownKeyError) Unwrap() error {
+	return ErrUnknownKey
+}
+
+// strict makes accessors panic on unknown keys instead of falling back to a default
+var strict atomic.Bool
+
+// SetStrict turns strict mode on or off, e.g. on in development so typos fail loudly
+func SetStrict(on bool) {
+	strict.Store(on)
+}
+
+// Strict reports whether strict mode is on
+func Strict() bool {
+	return strict.Load()
+}
+
+// unknownKey builds the error for key missing from values
+func unknownKey(mapName, key string, values map[string]string) *UnknownKeyError {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	return &UnknownKeyError{Map: mapName, Key: key, Suggestion: closestKey(key, keys)}
+}
+
+// Lookup returns the value of an environment key; secrets are not returned
+func (c *Omnitags) Lookup(key string) (string, error) {
+	if value, exists := c.Aliases[key]; exists {
+		return value, nil
+	}
+	if _, secret := c.Secrets.Get(key); secret {
+		return "", fmt.Errorf("omnitags key %q is a secret, read it through Secrets", key)
+	}
+	return "", unknownKey("aliases", key, c.Aliases)
+}
+
+// LookupIn returns the value of key in a derived map named as in naming rules, e.g. LookupIn("titles", "tabel_c2_v2")
+func (c *Omnitags) LookupIn(mapName, key string) (string, error) {
+	values, known := c.derivedMaps()[mapName]
+	if !known {
+		return "", fmt.Errorf("unknown omnitags map %q, expected one of %s", mapName, strings.Join(DerivedMapNames(), ", "))
+	}
+	if value, exists := values[key]; exists {
+		return value, nil
+	}
+	return "", unknownKey(mapName, key, values)
+}
+
+// Table returns the table with the given code, key or name
+func (c *Omnitags) Table(table string) (*Table, error) {
+	if t := c.FindTable(table); t != nil {
+		return t, nil
+	}
+	names := make(map[string]string, 3*len(c.Schema.Tables))
+	for _, t := range c.Schema.Tables {
+		names[t.Code()], names[t.Key], names[t.Name] = t.Key, t.Key, t.Key
+	}
+	return nil, unknownKey("tables", table, names)
+}
+
+// MustTable is Table for tables the code cannot work without; it panics when the table is missing
+func (c *Omnitags) MustTable(table string) *Table {
+	t, err := c.Table(table)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Field returns field number index of a table, e.g. Field("c2", 6) is tabel_c2_field6
+func (c *Omnitags) Field(table string, index int) (*Field, error) {
+	t, err := c.Table(table)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range t.Fields {
+		if f.Index == index {
+			return f, nil
+		}
+	}
+	return nil, &UnknownKeyError{Map: "fields", Key: fmt.Sprintf("%s_field%d", t.Key, index)}
+}
+
+// closestKey returns the key within two edits of key, or "" when there is none
+func closestKey(key string, keys []string) string {
+	sort.Strings(keys)
+	best, bestDistance := "", 3
+	for _, k := range keys {
+		if d := editDistance(key, k); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Migration holds the statements that move a database between two schema versions
+type Migration struct {
+	Up   []string
+	Down []string
+}
+
+// Empty reports whether the two schema versions need no database changes
+func (m Migration) Empty() bool {
+	return len(m.Up) == 0
+}
+
+// UpSQL renders the up statements as a script
+func (m Migration) UpSQL() string {
+	return strings.Join(m.Up, "\n")
+}
+
+// DownSQL renders the down statements as a script
+func (m Migration) DownSQL() string {
+	return strings.Join(m.Down, "\n")
+}
+
+// creatable reports whether a table has enough information for a CREATE TABLE
+func creatable(t *Table) bool {
+	return t != nil && t.Name != "" && t.PrimaryKey() != nil
+}
+
+// GenerateMigration computes an incremental migration between two versions of the environment file.
+// Tables are matched by their tabel_* key and fields by their field key, so a changed value is a rename.
+func GenerateMigration(from, to *Schema, d Dialect) Migration {
+	var m Migration
+	var down []string
+
+	for _, newTable := range to.Tables {
+		oldTable := from.Table(newTable.Code())
+		switch {
+		case !creatable(newTable) && !creatable(oldTable):
+			continue
+		case !creatable(oldTable):
+			m.Up = append(m.Up, d.CreateTable(newTable))
+			down = append(down, d.DropTable(newTable))
+		case !creatable(newTable):
+			m.Up = append(m.Up, d.DropTable(oldTable))
+			down = append(down, d.CreateTable(oldTable))
+		default:
+			up, rev := d.alterTable(oldTable, newTable)
+			m.Up = append(m.Up, up...)
+			down = append(down, rev...)
+		}
+	}
+
+	for _, oldTable := range from.Tables {
+		if creatable(oldTable) && to.Table(oldTable.Code()) == nil {
+			m.Up = append(m.Up, d.DropTable(oldTable))
+			down = append(down, d.CreateTable(oldTable))
+		}
+	}
+
+	// Down statements undo the up statements in reverse order
+	for i := len(down) - 1; i >= 0; i-- {
+		m.Down = append(m.Down, down[i])
+	}
+	return m
+}
+
+// alterTable returns the up statements and their matching down statements for a changed table
+func (d Dialect) alterTable(from, to *Table) ([]string, []string) {
+	if d == DialectSQLite && sqliteNeedsRebuild(from, to) {
+		return []string{d.rebuildTable(from, to)}, []string{d.rebuildTable(to, from)}
+	}
+
+	var up, down []string
+	if from.Name != to.Name {
+		up = append(up, d.renameTable(from.Name, to.Name))
+		down = append(down, d.renameTable(to.Name, from.Name))
+	}
+
+	// MySQL moves the primary key in one statement, an AUTO_INCREMENT column must be a key when it is added
+	table := d.quote(to.Name)
+	oldPK, newPK := from.PrimaryKey(), to.PrimaryKey()
+	movePK := d == DialectMySQL && oldPK.Key != newPK.Key
+	var pkUp, pkDown []string
+	alter := func(oldField, newField *Field) {
+		u, rev := d.alterColumn(from, to, oldField, newField)
+		switch {
+		case u == "":
+		case movePK && (oldField != nil && oldField.Key == oldPK.Key || newField != nil && newField.Key == newPK.Key):
+			pkUp = append(pkUp, u)
+			pkDown = append(pkDown, rev)
+		default:
+			up = append(up, fmt.Sprintf("ALTER TABLE %s %s;\n", table, u))
+			down = append(down, fmt.Sprintf("ALTER TABLE %s %s;\n", table, rev))
+		}
+	}
+
+	for _, newField := range to.Fields {
+		if newField.Name != "" {
+			alter(fieldByKey(from, newField.Key), newField)
+		}
+	}
+	for _, oldField := range from.Fields {
+		if newField := fieldByKey(to, oldField.Key); oldField.Name != "" && (newField == nil || newField.Name == "") {
+			alter(oldField, nil)
+		}
+	}
+
+	if movePK {
+		up = append(up, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY, %s, ADD PRIMARY KEY (%s);\n", table, strings.Join(pkUp, ", "), d.quote(newPK.Name)))
+		down = append(down, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY, %s, ADD PRIMARY KEY (%s);\n", table, strings.Join(pkDown, ", "), d.quote(oldPK.Name)))
+	}
+	return up, down
+}
+
+// alterColumn returns the ALTER TABLE clause turning oldField of from into newField of to and the clause undoing it,
+// or empty clauses when the column is unchanged; a nil or unnamed field is a column that does not exist
+func (d Dialect) alterColumn(from, to *Table, oldField, newField *Field) (string, string) {
+	switch {
+	case newField == nil || newField.Name == "":
+		return "DROP COLUMN " + d.quote(oldField.Name), "ADD COLUMN " + d.columnDefinition(from, oldField)
+	case oldField == nil || oldField.Name == "":
+		return "ADD COLUMN " + d.columnDefinition(to, newField), "DROP COLUMN " + d.quote(newField.Name)
+	case d.columnDefinition(from, oldField) == d.columnDefinition(to, newField):
+		return "", ""
+	case d == DialectMySQL:
+		return fmt.Sprintf("CHANGE COLUMN %s %s", d.quote(oldField.Name), d.columnDefinition(to, newField)),
+			fmt.Sprintf("CHANGE COLUMN %s %s", d.quote(newField.Name), d.columnDefinition(from, oldField))
+	default:
+		return fmt.Sprintf("RENAME COLUMN %s TO %s", d.quote(oldField.Name), d.quote(newField.Name)),
+			fmt.Sprintf("RENAME COLUMN %s TO %s", d.quote(newField.Name), d.quote(oldField.Name))
+	}
+}
+
+func (d Dialect) renameTable(from, to string) string {
+	if d == DialectMySQL {
+		return fmt.Sprintf("RENAME TABLE %s TO %s;\n", d.quote(from), d.quote(to))
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;\n", d.quote(from), d.quote(to))
+}
+
+// sqliteNeedsRebuild reports whether a column changed its type or constraints,
+// which SQLite cannot express with ALTER TABLE
+func sqliteNeedsRebuild(from, to *Table) bool {
+	if from.PrimaryKey().Key != to.PrimaryKey().Key {
+		return true
+	}
+	for _, newField := range to.Fields {
+		oldField := fieldByKey(from, newField.Key)
+		if oldField == nil || oldField.Name == "" || newField.Name == "" {
+			continue
+		}
+		// The type follows the column name, compare it with the old name in an enum CHECK replaced by the new one
+		oldType := strings.ReplaceAll(DialectSQLite.columnType(oldField), DialectSQLite.quote(oldField.Name), DialectSQLite.quote(newField.Name))
+		if oldType != DialectSQLite.columnType(newField) {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildTable copies a table into a freshly created one, the SQLite way of altering columns
+func (d Dialect) rebuildTable(from, to *Table) string {
+	tmp := to.Name + "__new"
+	var fromColumns, toColumns []string
+	for _, newField := range to.Fields {
+		if oldField := fieldByKey(from, newField.Key); oldField != nil && oldField.Name != "" && newField.Name != "" {
+			fromColumns = append(fromColumns, d.quote(oldField.Name))
+			toColumns = append(toColumns, d.quote(newField.Name))
+		}
+	}
+
+	created := *to
+	created.Name = tmp
+	var b strings.Builder
+	b.WriteString(d.CreateTable(&created))
+	if len(toColumns) > 0 {
+		fmt.Fprintf(&b, "INSERT INTO %s (%s) SELECT %s FROM %s;\n", d.quote(tmp), strings.Join(toColumns, ", "), strings.Join(fromColumns, ", "), d.quote(from.Name))
+	}
+	fmt.Fprintf(&b, "DROP TABLE %s;\n", d.quote(from.Name))
+	b.WriteString(d.renameTable(tmp, to.Name))
+	return b.String()
+}
+
+func fieldByKey(t *Table, key string) *Field {
+	for _, f := range t.Fields {
+		if f.Key == key {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGenerateMigration(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		changes []string
+		up      []string
+		down    []string
+	}{
+		{
+			name: "no changes", dialect: DialectMySQL,
+		},
+		{
+			name: "alias only", dialect: DialectSQLite,
+			changes: []string{"tabel_f3_field2_alias", "Name"},
+		},
+		{
+			name: "mysql rename column", dialect: DialectMySQL,
+			changes: []string{"tabel_f3_field2", "nama_pembeli"},
+			up:      []string{"ALTER TABLE `transaksi` CHANGE COLUMN `nama` `nama_pembeli` VARCHAR(255) NULL;\n"},
+			down:    []string{"ALTER TABLE `transaksi` CHANGE COLUMN `nama_pembeli` `nama` VARCHAR(255) NULL;\n"},
+		},
+		{
+			name: "mysql change type", dialect: DialectMySQL,
+			changes: []string{"tabel_f3_field2", "tgl_bayar"},
+			up:      []string{"ALTER TABLE `transaksi` CHANGE COLUMN `nama` `tgl_bayar` DATETIME NULL;\n"},
+			down:    []string{"ALTER TABLE `transaksi` CHANGE COLUMN `tgl_bayar` `nama` VARCHAR(255) NULL;\n"},
+		},
+		{
+			name: "mysql rename table", dialect: DialectMySQL,
+			changes: []string{"tabel_f3", "trx"},
+			up:      []string{"RENAME TABLE `transaksi` TO `trx`;\n"},
+			down:    []string{"RENAME TABLE `trx` TO `transaksi`;\n"},
+		},
+		{
+			name: "mysql add column", dialect: DialectMySQL,
+			changes: []string{"tabel_f3_field4", "harga"},
+			up:      []string{"ALTER TABLE `transaksi` ADD COLUMN `harga` INT NULL;\n"},
+			down:    []string{"ALTER TABLE `transaksi` DROP COLUMN `harga`;\n"},
+		},
+		{
+			name: "mysql enum value added", dialect: DialectMySQL,
+			changes: []string{"tabel_f3_field3_value3", "qris"},
+			up:      []string{"ALTER TABLE `transaksi` CHANGE COLUMN `metode` `metode` ENUM('cash','transfer','qris') NULL;\n"},
+			down:    []string{"ALTER TABLE `transaksi` CHANGE COLUMN `metode` `metode` ENUM('cash','transfer') NULL;\n"},
+		},
+		{
+			name: "mysql drop table", dialect: DialectMySQL,
+			changes: []string{"tabel_f3", ""},
+			up:      []string{"DROP TABLE IF EXISTS `transaksi`;\n"},
+			down:    []string{"-- tabel_f3: Transaksi\nCREATE TABLE `transaksi` (\n  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n  `nama` VARCHAR(255) NULL,\n  `metode` ENUM('cash','transfer') NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n"},
+		},
+		{
+			name: "sqlite rename column", dialect: DialectSQLite,
+			changes: []string{"tabel_f3_field2", "nama_pembeli"},
+			up:      []string{"ALTER TABLE \"transaksi\" RENAME COLUMN \"nama\" TO \"nama_pembeli\";\n"},
+			down:    []string{"ALTER TABLE \"transaksi\" RENAME COLUMN \"nama_pembeli\" TO \"nama\";\n"},
+		},
+		{
+			name: "sqlite rename table", dialect: DialectSQLite,
+			changes: []string{"tabel_f3", "trx"},
+			up:      []string{"ALTER TABLE \"transaksi\" RENAME TO \"trx\";\n"},
+			down:    []string{"ALTER TABLE \"trx\" RENAME TO \"transaksi\";\n"},
+		},
+		{
+			name: "sqlite add column", dialect: DialectSQLite,
+			changes: []string{"tabel_f3_field4", "harga"},
+			up:      []string{"ALTER TABLE \"transaksi\" ADD COLUMN \"harga\" INTEGER NULL;\n"},
+			down:    []string{"ALTER TABLE \"transaksi\" DROP COLUMN \"harga\";\n"},
+		},
+		{
+			name: "sqlite change type rebuilds", dialect: DialectSQLite,
+			changes: []string{"tabel_f3_field2", "tgl_bayar"},
+			up: []string{"-- tabel_f3: Transaksi\nCREATE TABLE \"transaksi__new\" (\n  \"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n  \"tgl_bayar\" DATETIME NULL,\n  \"metode\" TEXT CHECK (\"metode\" IN ('cash','transfer')) NULL\n);\n" +
+				"INSERT INTO \"transaksi__new\" (\"id\", \"tgl_bayar\", \"metode\") SELECT \"id\", \"nama\", \"metode\" FROM \"transaksi\";\n" +
+				"DROP TABLE \"transaksi\";\nALTER TABLE \"transaksi__new\" RENAME TO \"transaksi\";\n"},
+			down: []string{"-- tabel_f3: Transaksi\nCREATE TABLE \"transaksi__new\" (\n  \"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n  \"nama\" TEXT NULL,\n  \"metode\" TEXT CHECK (\"metode\" IN ('cash','transfer')) NULL\n);\n" +
+				"INSERT INTO \"transaksi__new\" (\"id\", \"nama\", \"metode\") SELECT \"id\", \"tgl_bayar\", \"metode\" FROM \"transaksi\";\n" +
+				"DROP TABLE \"transaksi\";\nALTER TABLE \"transaksi__new\" RENAME TO \"transaksi\";\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := GenerateMigration(diffVersion(), diffVersion(tt.changes...), tt.dialect)
+			if !reflect.DeepEqual(m.Up, tt.up) {
+				t.Errorf("up:\n%s\nwant:\n%s", m.UpSQL(), strings.Join(tt.up, "\n"))
+			}
+			if !reflect.DeepEqual(m.Down, tt.down) {
+				t.Errorf("down:\n%s\nwant:\n%s", m.DownSQL(), strings.Join(tt.down, "\n"))
+			}
+			if m.Empty() != (len(tt.up) == 0) {
+				t.Errorf("Empty = %v with %d up statements", m.Empty(), len(m.Up))
+			}
+		})
+	}
+}
+
+func TestGenerateMigrationMySQLPrimaryKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to *Schema
+		up       []string
+		down     []string
+	}{
+		{
+			name: "id added", from: diffVersion("tabel_f3_field1", ""), to: diffVersion(),
+			up:   []string{"ALTER TABLE `transaksi` DROP PRIMARY KEY, ADD COLUMN `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, CHANGE COLUMN `nama` `nama` VARCHAR(255) NULL, ADD PRIMARY KEY (`id`);\n"},
+			down: []string{"ALTER TABLE `transaksi` DROP PRIMARY KEY, DROP COLUMN `id`, CHANGE COLUMN `nama` `nama` VARCHAR(255) NOT NULL, ADD PRIMARY KEY (`nama`);\n"},
+		},
+		{
+			name: "column renamed to id", from: diffVersion("tabel_f3_field1", "", "tabel_f3_field4", "harga"), to: diffVersion("tabel_f3_field1", "", "tabel_f3_field4", "id"),
+			up:   []string{"ALTER TABLE `transaksi` DROP PRIMARY KEY, CHANGE COLUMN `nama` `nama` VARCHAR(255) NULL, CHANGE COLUMN `harga` `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, ADD PRIMARY KEY (`id`);\n"},
+			down: []string{"ALTER TABLE `transaksi` DROP PRIMARY KEY, CHANGE COLUMN `nama` `nama` VARCHAR(255) NOT NULL, CHANGE COLUMN `id` `harga` INT NULL, ADD PRIMARY KEY (`nama`);\n"},
+		},
+		{
+			name: "primary key renamed in place", from: diffVersion(), to: diffVersion("tabel_f3_field1", "kode"),
+			up:   []string{"ALTER TABLE `transaksi` CHANGE COLUMN `id` `kode` VARCHAR(255) NOT NULL;\n"},
+			down: []string{"ALTER TABLE `transaksi` CHANGE COLUMN `kode` `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := GenerateMigration(tt.from, tt.to, DialectMySQL)
+			if !reflect.DeepEqual(m.Up, tt.up) {
+				t.Errorf("up:\n%s\nwant:\n%s", m.UpSQL(), strings.Join(tt.up, "\n"))
+			}
+			if !reflect.DeepEqual(m.Down, tt.down) {
+				t.Errorf("down:\n%s\nwant:\n%s", m.DownSQL(), strings.Join(tt.down, "\n"))
+			}
+		})
+	}
+}
+
+// sqliteColumns returns the sorted column names of every table of db
+func sqliteColumns(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var tables []string
+	if err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite!_%' ESCAPE '!'").Scan(&tables).Error; err != nil {
+		t.Fatal(err)
+	}
+	var columns []string
+	for _, table := range tables {
+		var names []string
+		if err := db.Raw("SELECT name FROM pragma_table_info(?)", table).Scan(&names).Error; err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			columns = append(columns, table+"."+name)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func TestGenerateMigrationRunsOnSQLite(t *testing.T) {
+	changes := [][]string{
+		{"tabel_f3_field2", "nama_pembeli"},
+		{"tabel_f3_field2", "tgl_bayar", "tabel_f3_field4", "harga"},
+		{"tabel_f3", "trx", "tabel_f3_field3_value3", "qris"},
+		{"tabel_f3_field2", "", "tabel_f4", "pelanggan", "tabel_f4_field1", "id"},
+	}
+	for _, change := range changes {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, to := diffVersion(), diffVersion(change...)
+		if err := db.Exec(GenerateDDL(from, DialectSQLite)).Error; err != nil {
+			t.Fatal(err)
+		}
+		before := sqliteColumns(t, db)
+
+		m := GenerateMigration(from, to, DialectSQLite)
+		if err := db.Exec(m.UpSQL()).Error; err != nil {
+			t.Fatalf("%v up: %v\n%s", change, err, m.UpSQL())
+		}
+		want := schemaColumns(to)
+		if got := sqliteColumns(t, db); !reflect.DeepEqual(got, want) {
+			t.Errorf("%v up: columns %v, want %v", change, got, want)
+		}
+
+		if err := db.Exec(m.DownSQL()).Error; err != nil {
+			t.Fatalf("%v down: %v\n%s", change, err, m.DownSQL())
+		}
+		if got := sqliteColumns(t, db); !reflect.DeepEqual(got, before) {
+			t.Errorf("%v down: columns %v, want %v", change, got, before)
+		}
+	}
+}
+
+// schemaColumns lists the table.column names the schema describes, sorted like sqliteColumns
+func schemaColumns(s *Schema) []string {
+	var columns []string
+	for _, table := range s.Tables {
+		for _, f := range table.Fields {
+			if f.Name != "" {
+				columns = append(columns, table.Name+"."+f.Name)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+// NamingRule derives one entry of a derived map from an environment key and its value.
+// Key and Value are text/template strings evaluated with .Key and .Value.
+type NamingRule struct {
+	Map   string `json:"map"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+
+	key   *template.Template
+	value *template.Template
+}
+
+// NamingConvention is the set of rules LoadData uses to fill the derived maps
+type NamingConvention struct {
+	Name string `json:"name"`
+	// Replace drops the default rules instead of overriding them rule by rule
+	Replace bool         `json:"replace"`
+	Rules   []NamingRule `json:"rules"`
+}
+
+// namingMaps lists the maps a rule may target
+var namingMaps = []string{"vinput", "vpost", "vget", "flash1msg", "flash", "flashfunc", "flashmsg", "vuploadpath", "views", "titles"}
+
+var namingFuncs = template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+}
+
+// defaultNamingRules are the CodeIgniter conventions of codeigniter3-omnitags
+var defaultNamingRules = []NamingRule{
+	// Input Fields
+	{Map: "vinput", Key: "{{.Key}}_input", Value: "txt_{{.Value}}"},
+	{Map: "vinput", Key: "{{.Key}}_filter1", Value: "min_{{.Value}}"},
+	{Map: "vinput", Key: "{{.Key}}_filter2", Value: "max_{{.Value}}"},
+	{Map: "vinput", Key: "{{.Key}}_old", Value: "old_{{.Value}}"},
+	{Map: "vinput", Key: "{{.Key}}_new", Value: "new_{{.Value}}"},
+	{Map: "vinput", Key: "{{.Key}}_confirm", Value: "confirm_{{.Value}}"},
+
+	// Post & Get Requests
+	{Map: "vpost", Key: "{{.Key}}", Value: "txt_{{.Value}}"},
+	{Map: "vpost", Key: "{{.Key}}_old", Value: "old_{{.Value}}"},
+	{Map: "vpost", Key: "{{.Key}}_new", Value: "new_{{.Value}}"},
+	{Map: "vpost", Key: "{{.Key}}_confirm", Value: "confirm_{{.Value}}"},
+	{Map: "vget", Key: "{{.Key}}", Value: "txt_{{.Value}}"},
+	{Map: "vget", Key: "{{.Key}}_filter1", Value: "min_{{.Value}}"},
+	{Map: "vget", Key: "{{.Key}}_filter2", Value: "max_{{.Value}}"},
+
+	// Flash Messages
+	{Map: "flash1msg", Key: "{{.Key}}", Value: "{{.Value}} successfully saved!"},
+	{Map: "flash", Key: "{{.Key}}", Value: "pesan_{{.Value}}"},
+	{Map: "flashfunc", Key: "{{.Key}}", Value: `$(".{{.Value}}").modal("show")`},
+	{Map: "flashmsg", Key: "{{.Key}}", Value: "{{.Value}} tidak bisa diupload!"},
+
+	// Upload Path
+	{Map: "vuploadpath", Key: "{{.Key}}", Value: "./assets/img/{{.Key}}/"},
+
+	// Views
+	{Map: "views", Key: "{{.Key}}", Value: "contents/{{.Key}}/index"},
+	{Map: "views", Key: "{{.Key}}_daftar", Value: "contents/{{.Key}}/daftar"},
+	{Map: "views", Key: "{{.Key}}_admin", Value: "contents/{{.Key}}/admin"},
+	{Map: "views", Key: "{{.Key}}_laporan", Value: "contents/{{.Key}}/laporan"},
+	{Map: "views", Key: "{{.Key}}_print", Value: "contents/{{.Key}}/print"},
+
+	// Titles
+	{Map: "titles", Key: "{{.Key}}_v1", Value: "{{.Value}}"},
+	{Map: "titles", Key: "{{.Key}}_v2", Value: "List of {{.Value}}"},
+	{Map: "titles", Key: "{{.Key}}_v3", Value: "{{.Value}} Data"},
+	{Map: "titles", Key: "{{.Key}}_v4", Value: "{{.Value}} Report"},
+	{Map: "titles", Key: "{{.Key}}_v5", Value: "{{.Value}} Data"},
+	{Map: "titles", Key: "{{.Key}}_v6", Value: "{{.Value}} Profile"},
+	{Map: "titles", Key: "{{.Key}}_v7", Value: "{{.Value}} Successful!"},
+}
+
+// DefaultNamingConvention returns the built-in CodeIgniter conventions
+func DefaultNamingConvention() *NamingConvention {
+	n := &NamingConvention{Name: "default", Rules: append([]NamingRule{}, defaultNamingRules...)}
+	if err := n.compile(); err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// LoadNamingConvention reads a JSON naming file. Its rules override the default rule
+// with the same map and key template and add the others, unless "replace" is set.
+func LoadNamingConvention(path string) (*NamingConvention, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var custom NamingConvention
+	if err := json.Unmarshal(file, &custom); err != nil {
+		return nil, fmt.Errorf("parsing naming file %q: %w", path, err)
+	}
+
+	n := &NamingConvention{Name: custom.Name, Replace: custom.Replace}
+	if !custom.Replace {
+		n.Rules = append(n.Rules, defaultNamingRules...)
+	}
+	for _, rule := range custom.Rules {
+		n.setRule(rule)
+	}
+
+	if err := n.compile(); err != nil {
+		return nil, fmt.Errorf("naming file %q: %w", path, err)
+	}
+	return n, nil
+}
+
+// setRule replaces the rule with the same map and key template, or appends it
+func (n *NamingConvention) setRule(rule NamingRule) {
+	rule.Map = strings.ToLower(rule.Map)
+	for i := range n.Rules {
+		if n.Rules[i].Map == rule.Map && n.Rules[i].Key == rule.Key {
+			n.Rules[i] = rule
+			return
+		}
+	}
+	n.Rules = append(n.Rules, rule)
+}
+
+// compile parses every template and checks it against a sample entry
+func (n *NamingConvention) compile() error {
+	for i := range n.Rules {
+		rule := &n.Rules[i]
+		if !util.Contains(rule.Map, namingMaps) {
+			return fmt.Errorf("rule %d targets unknown map %q, expected one of %s", i+1, rule.Map, strings.Join(namingMaps, ", "))
+		}
+
+		var err error
+		if rule.key, err = template.New("key").Funcs(namingFuncs).Option("missingkey=error").Parse(rule.Key); err != nil {
+			return fmt.Errorf("rule %d key: %w", i+1, err)
+		}
+		if rule.value, err = template.New("value").Funcs(namingFuncs).Option("missingkey=error").Parse(rule.Value); err != nil {
+			return fmt.Errorf("rule %d value: %w", i+1, err)
+		}
+		if _, _, err := rule.derive("tabel_a1", "ot_website"); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// derive evaluates the rule for one entry
+func (r *NamingRule) derive(key, value string) (string, string, error) {
+	data := struct{ Key, Value string }{key, value}
+
+	var k, v bytes.Buffer
+	if err := r.key.Execute(&k, data); err != nil {
+		return "", "", err
+	}
+	if err := r.value.Execute(&v, data); err != nil {
+		return "", "", err
+	}
+	return k.String(), v.String(), nil
+}
+
+// apply fills the derived maps of c for one entry and records it as the source of each derived key
+func (n *NamingConvention) apply(c *Omnitags, key, value string) {
+	maps := c.derivedMaps()
+	for i := range n.Rules {
+		rule := &n.Rules[i]
+		// Templates were checked by compile, so execution only fails on write errors
+		if k, v, err := rule.derive(key, value); err == nil {
+			maps[rule.Map][k] = v
+			if c.DerivedFrom[rule.Map] == nil {
+				c.DerivedFrom[rule.Map] = make(map[string]string)
+			}
+			c.DerivedFrom[rule.Map][k] = key
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeNaming writes a naming file and returns its path
+func writeNaming(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "naming.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// loadWithNaming loads data with the naming convention n
+func loadWithNaming(n *NamingConvention, data map[string]interface{}) *Omnitags {
+	c := NewConfig()
+	c.Naming = n
+	c.LoadData(data)
+	return c
+}
+
+func TestDefaultNamingConvention(t *testing.T) {
+	c := loadTestOmnitags(environment("tabel_c2", "users"))
+	tests := []struct {
+		values map[string]string
+		key    string
+		want   string
+	}{
+		{c.VInput, "tabel_c2_input", "txt_users"},
+		{c.VInput, "tabel_c2_filter2", "max_users"},
+		{c.VPost, "tabel_c2_confirm", "confirm_users"},
+		{c.VGet, "tabel_c2_filter1", "min_users"},
+		{c.Flash1Msg, "tabel_c2", "users successfully saved!"},
+		{c.Flash, "tabel_c2", "pesan_users"},
+		{c.FlashFunc, "tabel_c2", `$(".users").modal("show")`},
+		{c.FlashMsg, "tabel_c2", "users tidak bisa diupload!"},
+		{c.VUploadPath, "tabel_c2", "./assets/img/tabel_c2/"},
+		{c.Views, "tabel_c2_laporan", "contents/tabel_c2/laporan"},
+		{c.Titles, "tabel_c2_v7", "users Successful!"},
+	}
+	for _, tt := range tests {
+		if got := tt.values[tt.key]; got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	if got := c.DerivedFrom["views"]["tabel_c2_laporan"]; got != "tabel_c2" {
+		t.Errorf("DerivedFrom[views][tabel_c2_laporan] = %q, want tabel_c2", got)
+	}
+}
+
+func TestLoadNamingConvention(t *testing.T) {
+	data := environment("tabel_c2", "Users")
+
+	t.Run("overrides and adds rules", func(t *testing.T) {
+		n, err := LoadNamingConvention(writeNaming(t, `{"name": "custom", "rules": [
+			{"map": "VINPUT", "key": "{{.Key}}_input", "value": "{{lower .Value}}"},
+			{"map": "views", "key": "{{.Key}}_edit", "value": "pages/{{replace .Key \"tabel_\" \"\"}}/{{upper .Value}}"}
+		]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := loadWithNaming(n, data)
+		if got := c.VInput["tabel_c2_input"]; got != "users" {
+			t.Errorf("overridden input = %q, want users", got)
+		}
+		if got := c.VInput["tabel_c2_filter1"]; got != "min_Users" {
+			t.Errorf("default filter = %q, want min_Users", got)
+		}
+		if got := c.Views["tabel_c2_edit"]; got != "pages/c2/USERS" {
+			t.Errorf("added view = %q, want pages/c2/USERS", got)
+		}
+		if n.Name != "custom" {
+			t.Errorf("Name = %q, want custom", n.Name)
+		}
+	})
+
+	t.Run("replace drops the defaults", func(t *testing.T) {
+		n, err := LoadNamingConvention(writeNaming(t, `{"replace": true, "rules": [{"map": "views", "key": "{{.Key}}", "value": "{{.Key}}.html"}]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := loadWithNaming(n, data)
+		if got := c.Views["tabel_c2"]; got != "tabel_c2.html" || len(c.Views) != 1 || len(c.VInput) != 0 {
+			t.Errorf("views = %v, inputs = %v, want only tabel_c2.html", c.Views, c.VInput)
+		}
+	})
+
+	t.Run("react example", func(t *testing.T) {
+		n, err := LoadNamingConvention("../naming.react.example.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := loadWithNaming(n, data)
+		if got := c.Views["tabel_c2_daftar"]; got != "pages/tabel_c2/List" {
+			t.Errorf("daftar view = %q, want pages/tabel_c2/List", got)
+		}
+		if got := c.VGet["tabel_c2_filter2"]; got != "Users_to" {
+			t.Errorf("filter2 = %q, want Users_to", got)
+		}
+	})
+
+	errs := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown map", `{"rules": [{"map": "routes", "key": "{{.Key}}", "value": "x"}]}`, `unknown map "routes"`},
+		{"unparsable template", `{"rules": [{"map": "views", "key": "{{.Key", "value": "x"}]}`, "unclosed action"},
+		{"unknown field", `{"rules": [{"map": "views", "key": "{{.Key}}", "value": "{{.Table}}"}]}`, "can't evaluate field Table"},
+		{"invalid json", `{"rules": [`, "parsing naming file"},
+	}
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadNamingConvention(writeNaming(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadNamingConvention = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
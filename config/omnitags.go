@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"sync"
 )
 
 // Omnitags holds the application's configuration values.
 type Omnitags struct {
 	Aliases     map[string]string
 	Reverse     map[string]string
+	ReverseKeys map[string][]string
 	VInput      map[string]string
 	VPost       map[string]string
 	VGet        map[string]string
@@ -20,18 +23,20 @@ type Omnitags struct {
 	VUploadPath map[string]string
 	Views       map[string]string
 	Titles      map[string]string
+	DerivedFrom map[string]map[string]string
 	V           map[int]string
 	TL          map[string]interface{}
+	Schema      *Schema
+	Secrets     Secrets
+	Naming      *NamingConvention
 }
 
-// global variable to hold the configuration
-var omnitagsConfig *Omnitags
-
 // NewConfig initializes a new instance of Omnitags with empty maps
 func NewConfig() *Omnitags {
 	c := &Omnitags{
 		Aliases:     make(map[string]string),
 		Reverse:     make(map[string]string),
+		ReverseKeys: make(map[string][]string),
 		VInput:      make(map[string]string),
 		VPost:       make(map[string]string),
 		VGet:        make(map[string]string),
@@ -42,129 +47,175 @@ func NewConfig() *Omnitags {
 		VUploadPath: make(map[string]string),
 		Views:       make(map[string]string),
 		Titles:      make(map[string]string),
+		DerivedFrom: make(map[string]map[string]string),
 		V:           make(map[int]string),
 		TL:          make(map[string]interface{}),
+		Schema:      NewSchema(),
+		Secrets:     NewSecrets(),
+		Naming:      DefaultNamingConvention(),
 	}
 
-	// Initialize `V` dynamically
-	for i := 1; i <= 11; i++ {
-		c.V[i] = fmt.Sprintf("contents/section_%d", i)
-		if i <= 6 {
-			c.Flash1Msg[fmt.Sprintf("flash_%d", i)] = fmt.Sprintf("Flash message %d", i)
-		}
-		if i <= 5 {
-			c.FlashMsg[fmt.Sprintf("error_%d", i)] = fmt.Sprintf("Error message %d", i)
-		}
-	}
-
-	// Initialize `TL` dynamically
+	// V and TL are completed from the file by LoadData
+	c.setSections(defaultSections)
 	c.TL["ot"] = nil
-	c.TL["a1"] = nil
-	groups := map[string]int{"b": 11, "c": 2, "d": 4, "e": 8, "f": 4}
-	for group, count := range groups {
-		for i := 1; i <= count; i++ {
-			c.TL[fmt.Sprintf("%s%d", group, i)] = nil
-		}
+
+	for i := 1; i <= 6; i++ {
+		c.Flash1Msg[fmt.Sprintf("flash_%d", i)] = fmt.Sprintf("Flash message %d", i)
+	}
+	for i := 1; i <= 5; i++ {
+		c.FlashMsg[fmt.Sprintf("error_%d", i)] = fmt.Sprintf("Error message %d", i)
 	}
 
 	return c
 }
 
-// LoadData extracts key-value pairs from JSON and initializes mappings
-func (c *Omnitags) LoadData(data map[string]interface{}) {
+// defaultSections is the number of V sections when the file has no view_sections setting
+const defaultSections = 11
+
+// setSections replaces V with n sections, contents/section_1 to contents/section_<n>
+func (c *Omnitags) setSections(n int) {
+	c.V = make(map[int]string, n)
+	for i := 1; i <= n; i++ {
+		c.V[i] = fmt.Sprintf("contents/section_%d", i)
+	}
+}
+
+// environmentEntry is a single item of the Postman "values" array
+type environmentEntry struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Secret reports whether the entry is marked `type: secret`
+func (e environmentEntry) Secret() bool {
+	return e.Type == "secret"
+}
+
+// environmentEntries returns the well-formed entries of the Postman "values" array in file order.
+// Entries without an `enabled` attribute count as enabled, as in Postman.
+func environmentEntries(data map[string]interface{}) []environmentEntry {
+	var entries []environmentEntry
 	if values, ok := data["values"].([]interface{}); ok {
 		for _, item := range values {
-			if obj, isObject := item.(map[string]interface{}); isObject {
-				key, keyExists := obj["key"].(string)
-				value, valueExists := obj["value"].(string)
-
-				if keyExists && valueExists {
-					// Aliases & Reverse Mapping
-					c.Aliases[key] = value
-					c.Reverse[value+"_realname"] = key
-
-					// Input Fields
-					c.VInput[key+"_input"] = "txt_" + value
-					c.VInput[key+"_filter1"] = "min_" + value
-					c.VInput[key+"_filter2"] = "max_" + value
-					c.VInput[key+"_old"] = "old_" + value
-					c.VInput[key+"_new"] = "new_" + value
-					c.VInput[key+"_confirm"] = "confirm_" + value
-
-					// Post & Get Requests
-					c.VPost[key] = "txt_" + value
-					c.VPost[key+"_old"] = "old_" + value
-					c.VPost[key+"_new"] = "new_" + value
-					c.VPost[key+"_confirm"] = "confirm_" + value
-
-					c.VGet[key] = "txt_" + value
-					c.VGet[key+"_filter1"] = "min_" + value
-					c.VGet[key+"_filter2"] = "max_" + value
-
-					// Flash Messages
-					c.Flash1Msg[key] = value + " successfully saved!"
-					c.Flash[key] = "pesan_" + value
-					c.FlashFunc[key] = "$(\"." + value + "\").modal(\"show\")"
-					c.FlashMsg[key] = value + " tidak bisa diupload!"
-
-					// Upload Path
-					c.VUploadPath[key] = "./assets/img/" + key + "/"
-
-					// Views
-					c.Views[key] = "contents/" + key + "/index"
-					c.Views[key+"_daftar"] = "contents/" + key + "/daftar"
-					c.Views[key+"_admin"] = "contents/" + key + "/admin"
-					c.Views[key+"_laporan"] = "contents/" + key + "/laporan"
-					c.Views[key+"_print"] = "contents/" + key + "/print"
-
-					// Titles
-					c.Titles[key+"_v1"] = value
-					c.Titles[key+"_v2"] = "List of " + value
-					c.Titles[key+"_v3"] = value + " Data"
-					c.Titles[key+"_v4"] = value + " Report"
-					c.Titles[key+"_v5"] = value + " Data"
-					c.Titles[key+"_v6"] = value + " Profile"
-					c.Titles[key+"_v7"] = value + " Successful!"
-				}
+			obj, isObject := item.(map[string]interface{})
+			if !isObject {
+				continue
+			}
+			key, keyExists := obj["key"].(string)
+			value, valueExists := obj["value"].(string)
+			if !keyExists || !valueExists {
+				continue
 			}
+			entry := environmentEntry{Key: key, Value: value, Enabled: true}
+			entry.Type, _ = obj["type"].(string)
+			if enabled, ok := obj["enabled"].(bool); ok {
+				entry.Enabled = enabled
+			}
+			entries = append(entries, entry)
 		}
 	}
+	return entries
 }
 
-// LoadConfig initializes the global configuration by reading the JSON file
-func ReadConfig() *Omnitags {
-	if omnitagsConfig == nil {
-		// Initialize a new Omnitags instance
-		omnitagsConfig = NewConfig()
-
-		// Hardcoded file path
-		filePath := "app.postman_environment.json"
+// LoadData extracts key-value pairs from JSON and initializes mappings.
+// Disabled entries are skipped, secret entries only go to Secrets and {{references}} are expanded,
+// unresolved ones are listed in Schema.VariableIssues. Group and field reference keys only go to Aliases.
+func (c *Omnitags) LoadData(data map[string]interface{}) {
+	c.Schema = ParseSchema(data)
 
-		// Read JSON file
-		file, err := os.ReadFile(filePath)
-		if err != nil {
-			fmt.Println("Error reading configuration file:", err)
-			return omnitagsConfig
+	entries, _ := resolvedEntries(data)
+	for _, entry := range entries {
+		if !entry.Enabled {
+			continue
+		}
+		if entry.Secret() {
+			c.Secrets.set(entry.Key, entry.Value)
+			continue
 		}
+		key, value := entry.Key, entry.Value
 
-		// Parse JSON into a map
-		var jsonData map[string]interface{}
-		if err := json.Unmarshal(file, &jsonData); err != nil {
-			fmt.Println("Error parsing configuration file:", err)
-			return omnitagsConfig
+		// Aliases & Reverse Mapping
+		c.Aliases[key] = value
+		if isMetadataKey(key) {
+			// Group labels and orders and field references describe the schema, they name no table or field
+			continue
 		}
+		c.Reverse[value+"_realname"] = key
+		c.ReverseKeys[value] = append(c.ReverseKeys[value], key)
 
-		// Load the parsed data into the Omnitags struct
-		omnitagsConfig.LoadData(jsonData)
+		// Input fields, requests, flash messages, upload paths, views and titles
+		c.Naming.apply(c, key, value)
 	}
 
-	return omnitagsConfig
+	// TL lists every table of the file, V has view_sections sections
+	for _, t := range c.Schema.Tables {
+		c.TL[t.Code()] = nil
+	}
+	if n, err := strconv.Atoi(c.Schema.Settings["view_sections"]); err == nil && n >= 0 {
+		c.setSections(n)
+	}
 }
 
-// GetValue fetches a value dynamically
+// ReadEnvironmentFile reads and parses a Postman environment file
+func ReadEnvironmentFile(filePath string) (map[string]interface{}, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonData map[string]interface{}
+	if err := json.Unmarshal(file, &jsonData); err != nil {
+		return nil, err
+	}
+	return jsonData, nil
+}
+
+// omnitagsSource is where ReadConfig loads the environment file from
+var omnitagsSource = OmnitagsSourceFromEnv()
+
+var omnitagsStore *OmnitagsStore
+var omnitagsErr error
+var omnitagsOnce sync.Once
+
+// SetOmnitagsSource overrides the source used by ReadConfig, e.g. from command line flags.
+// It must be called before the first ReadConfig or OmnitagsConfigStore call.
+func SetOmnitagsSource(s OmnitagsSource) {
+	omnitagsSource = s
+}
+
+// OmnitagsConfigStore returns the singleton store, loading it on first use
+func OmnitagsConfigStore() (*OmnitagsStore, error) {
+	omnitagsOnce.Do(func() {
+		omnitagsStore, omnitagsErr = NewOmnitagsStore(omnitagsSource)
+	})
+	return omnitagsStore, omnitagsErr
+}
+
+// ReadConfig returns the current snapshot of the global configuration
+func ReadConfig() (*Omnitags, error) {
+	store, err := OmnitagsConfigStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(), nil
+}
+
+// Validate checks that the loaded data is usable
+func (c *Omnitags) Validate() error {
+	return c.Schema.Validate()
+}
+
+// GetValue fetches a value dynamically, "Unknown Field" when it is missing; it panics in strict mode.
+// Prefer Lookup, which returns an error instead.
 func (c *Omnitags) GetValue(field string) string {
-	if value, exists := c.Aliases[field]; exists {
-		return value
+	value, err := c.Lookup(field)
+	if err != nil {
+		if Strict() {
+			panic(err)
+		}
+		return "Unknown Field"
 	}
-	return "Unknown Field"
+	return value
 }
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoadDataSkipsMetadataKeys(t *testing.T) {
+	c := loadTestOmnitags(environment(
+		"tabel_e_alias", "Events",
+		"tabel_e_order", "2",
+		"tabel_e3", "tiket",
+		"tabel_e3_field1", "id_tiket",
+		"tabel_e3_field5", "acara",
+		"tabel_e3_field5_ref", "e1",
+		"tabel_e1", "acara",
+		"tabel_e1_field1", "id_acara",
+	))
+	metadata := []string{"tabel_e_alias", "tabel_e_order", "tabel_e3_field5_ref"}
+
+	for _, key := range metadata {
+		if _, exists := c.Aliases[key]; !exists {
+			t.Errorf("Aliases is missing %s", key)
+		}
+		if keys := c.ReverseKeys[c.Aliases[key]]; containsString(keys, key) {
+			t.Errorf("ReverseKeys[%q] = %v, want no %s", c.Aliases[key], keys, key)
+		}
+	}
+	if got := c.Reverse["e1_realname"]; got != "" {
+		t.Errorf("Reverse[e1_realname] = %q, want none", got)
+	}
+
+	derived := map[string]map[string]string{
+		"VInput": c.VInput, "VPost": c.VPost, "VGet": c.VGet,
+		"Flash1Msg": c.Flash1Msg, "Flash": c.Flash, "FlashFunc": c.FlashFunc, "FlashMsg": c.FlashMsg,
+		"VUploadPath": c.VUploadPath, "Views": c.Views, "Titles": c.Titles,
+	}
+	for name, m := range derived {
+		for derivedKey := range m {
+			for _, key := range metadata {
+				if strings.HasPrefix(derivedKey, key) {
+					t.Errorf("%s has %s, derived from %s", name, derivedKey, key)
+				}
+			}
+		}
+	}
+	for name, sources := range c.DerivedFrom {
+		for derivedKey, source := range sources {
+			if containsString(metadata, source) {
+				t.Errorf("DerivedFrom[%s][%s] = %s", name, derivedKey, source)
+			}
+		}
+	}
+	// The table and field keys still get their entries
+	if c.VUploadPath["tabel_e3_field5"] == "" || c.Views["tabel_e3"] == "" {
+		t.Error("entries of tabel_e3 and tabel_e3_field5 are missing")
+	}
+
+	var buf bytes.Buffer
+	if err := exportDotenv(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		upper := strings.ToUpper(line)
+		if strings.Contains(upper, "FIELD5_REF") && !strings.Contains(upper, "ALIASES") {
+			t.Errorf("dotenv export has %s", line)
+		}
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
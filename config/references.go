@@ -0,0 +1,167 @@
+package config
+
+import (
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeyRef is a tabel_* key written in a source file
+type KeyRef struct {
+	Key  string `json:"key"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Suggestion is the closest known key of an unknown reference
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// KeyReport lists the references to keys that do not exist and the environment keys nothing refers to
+type KeyReport struct {
+	Unknown []KeyRef `json:"unknown"`
+	Unused  []string `json:"unused"`
+}
+
+// referencedKeyPattern matches keys such as tabel_c2, tabel_c2_field6_alias or tabel_c2_v2
+var referencedKeyPattern = regexp.MustCompile(`\btabel_[a-z]+\d+(?:_[a-z0-9]+)*\b`)
+
+// ignoreMarker excludes the references of a line, e.g. in examples
+const ignoreMarker = "omnitags:ignore"
+
+// referenceExtensions are the template files scanned as plain text; Go files are scanned for string literals
+var referenceExtensions = map[string]bool{".html": true, ".tmpl": true, ".gohtml": true, ".php": true}
+
+// ReferenceOptions tunes CheckReferences
+type ReferenceOptions struct {
+	// Tests also scans _test.go files, whose fixtures usually describe environments of their own
+	Tests bool
+}
+
+// CheckReferences scans the Go string literals and the templates below roots for tabel_* keys.
+// A reference is known when it is an environment key, a secret or a key of a derived map.
+// Hidden directories, vendor, node_modules, lines containing "omnitags:ignore" and,
+// unless opts.Tests is set, _test.go files are skipped.
+func (c *Omnitags) CheckReferences(opts ReferenceOptions, roots ...string) (*KeyReport, error) {
+	known := make(map[string]bool)
+	for key := range c.Aliases {
+		known[key] = true
+	}
+	for _, key := range c.Secrets.Keys() {
+		known[key] = true
+	}
+	for _, values := range c.derivedMaps() {
+		for key := range values {
+			known[key] = true
+		}
+	}
+	knownKeys := make([]string, 0, len(known))
+	for key := range known {
+		knownKeys = append(knownKeys, key)
+	}
+
+	report := &KeyReport{Unknown: []KeyRef{}, Unused: []string{}}
+	used := make(map[string]bool)
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "node_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			var refs []KeyRef
+			switch ext := filepath.Ext(path); {
+			case ext == ".go" && strings.HasSuffix(path, "_test.go") && !opts.Tests:
+			case ext == ".go":
+				refs, err = goKeyRefs(path)
+			case referenceExtensions[ext]:
+				refs, err = textKeyRefs(path)
+			}
+			if err != nil {
+				return err
+			}
+			for _, ref := range refs {
+				used[ref.Key] = true
+				if !known[ref.Key] {
+					ref.Suggestion = closestKey(ref.Key, knownKeys)
+					report.Unknown = append(report.Unknown, ref)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for key := range c.Aliases {
+		if referencedKeyPattern.MatchString(key) && !used[key] {
+			report.Unused = append(report.Unused, key)
+		}
+	}
+	sort.Strings(report.Unused)
+	return report, nil
+}
+
+// goKeyRefs returns the keys found in the string literals of a Go file
+func goKeyRefs(path string) ([]KeyRef, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(src), "\n")
+	var refs []KeyRef
+	fset := token.NewFileSet()
+	var s scanner.Scanner
+	s.Init(fset.AddFile(path, -1, len(src)), src, nil, 0)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.STRING {
+			continue
+		}
+		value, err := strconv.Unquote(lit)
+		if err != nil {
+			continue
+		}
+		line := fset.Position(pos).Line
+		if strings.Contains(lines[line-1], ignoreMarker) {
+			continue
+		}
+		for _, key := range referencedKeyPattern.FindAllString(value, -1) {
+			refs = append(refs, KeyRef{Key: key, File: path, Line: line})
+		}
+	}
+	return refs, nil
+}
+
+// textKeyRefs returns the keys found anywhere in a template file
+func textKeyRefs(path string) ([]KeyRef, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []KeyRef
+	for i, line := range strings.Split(string(src), "\n") {
+		if strings.Contains(line, ignoreMarker) {
+			continue
+		}
+		for _, key := range referencedKeyPattern.FindAllString(line, -1) {
+			refs = append(refs, KeyRef{Key: key, File: path, Line: i + 1})
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"strings"
+)
+
+// RefNone is the _ref value that turns off inference for a field, e.g. tabel_f1_field2_ref=none
+const RefNone = "none"
+
+// Relation is a foreign key from a field to a field of another table, usually its primary key
+type Relation struct {
+	Table   *Table
+	Field   *Field
+	Target  *Table
+	ToField *Field
+	// Declared is set when the relation comes from a _ref entry instead of the naming convention
+	Declared bool
+}
+
+// Relations returns the foreign keys of every table in schema order.
+// A tabel_<t>_field<m>_ref entry names the target as a table code, key or name, optionally
+// followed by .column, e.g. "c2", "tabel_c2" or "users.id". Other id_<name> and <name>_id fields
+// reference the table called <name>, its plural or its ot_ variant, e.g. id_user references users
+// and id_theme references ot_themes. Unresolved fields are reported by Lint.
+func (s *Schema) Relations() []*Relation {
+	relations, _ := s.resolveRelations()
+	return relations
+}
+
+// resolveRelations returns the relations of the schema and the problems found while resolving them
+func (s *Schema) resolveRelations() ([]*Relation, []LintIssue) {
+	l := &linter{}
+	var relations []*Relation
+	for _, t := range s.Tables {
+		for _, f := range t.Fields {
+			if f.Ref != "" {
+				if r := s.declaredRelation(l, t, f); r != nil {
+					relations = append(relations, r)
+				}
+				continue
+			}
+
+			stem := foreignKeyStem(f.Name)
+			if stem == "" || f == t.PrimaryKey() {
+				continue
+			}
+			target := s.inferTarget(stem)
+			if target == nil {
+				l.add(SeverityInfo, "relation-unresolved", f.Key+"_ref", "%s (%s.%s) looks like a foreign key but no table is called %s, declare it with %s_ref or set it to %s", f.Key, t.Name, f.Name, stem, f.Key, RefNone)
+				continue
+			}
+			relations = append(relations, &Relation{Table: t, Field: f, Target: target, ToField: target.PrimaryKey()})
+		}
+	}
+	return relations, l.issues
+}
+
+// declaredRelation resolves the _ref entry of a field, nil when it is "none" or invalid
+func (s *Schema) declaredRelation(l *linter, t *Table, f *Field) *Relation {
+	if f.Ref == RefNone {
+		return nil
+	}
+	name, column, _ := strings.Cut(f.Ref, ".")
+	target := s.findTable(name)
+	if target == nil {
+		l.add(SeverityError, "relation-unknown-table", f.Key+"_ref", "%s_ref references table %q, which the file does not define", f.Key, name)
+		return nil
+	}
+	to := target.PrimaryKey()
+	if column != "" {
+		to = target.Field(column)
+	}
+	if to == nil {
+		l.add(SeverityError, "relation-unknown-field", f.Key+"_ref", "%s_ref references %q, but %s has no such field", f.Key, f.Ref, target.Name)
+		return nil
+	}
+	return &Relation{Table: t, Field: f, Target: target, ToField: to, Declared: true}
+}
+
+// findTable returns the table with the given code, key or name, or nil
+func (s *Schema) findTable(name string) *Table {
+	if t := s.Table(strings.TrimPrefix(name, "tabel_")); t != nil {
+		return t
+	}
+	return s.TableByName(name)
+}
+
+// foreignKeyStem returns the referenced name of an id_<name> or <name>_id field, or ""
+func foreignKeyStem(name string) string {
+	if stem, found := strings.CutPrefix(name, "id_"); found {
+		return stem
+	}
+	if stem, found := strings.CutSuffix(name, "_id"); found {
+		return stem
+	}
+	return ""
+}
+
+// inferTarget returns the first table named after stem: stem itself, its plural, then the ot_ variants
+func (s *Schema) inferTarget(stem string) *Table {
+	names := []string{stem, stem + "s", stem + "es"}
+	if base, found := strings.CutSuffix(stem, "y"); found {
+		names = append(names, base+"ies")
+	}
+	for _, prefix := range []string{"", "ot_"} {
+		for _, name := range names {
+			if t := s.TableByName(prefix + name); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// relationsTestSchema has inferred, declared and broken references from tabel_f3
+func relationsTestSchema() *Schema {
+	return ParseSchema(environment(
+		"tabel_c2", "users",
+		"tabel_c2_field1", "id",
+		"tabel_c2_field2", "email",
+		"tabel_b1", "ot_themes",
+		"tabel_b1_field1", "id_theme",
+		"tabel_b2", "categories",
+		"tabel_b2_field1", "id_category",
+		"tabel_f1", "pelanggan",
+		"tabel_f1_field1", "id_pelanggan",
+		"tabel_f3", "transaksi",
+		"tabel_f3_field1", "id_transaksi",
+		"tabel_f3_field2", "id_user",
+		"tabel_f3_field3", "theme_id",
+		"tabel_f3_field4", "id_category",
+		"tabel_f3_field5", "id_cabang",
+		"tabel_f3_field6", "id_invoice",
+		"tabel_f3_field6_ref", RefNone,
+		"tabel_f3_field7", "kasir",
+		"tabel_f3_field7_ref", "c2",
+		"tabel_f3_field8", "email_pelanggan",
+		"tabel_f3_field8_ref", "users.email",
+		"tabel_f3_field9", "pembeli",
+		"tabel_f3_field9_ref", "tabel_f1",
+		"tabel_f3_field10", "gudang",
+		"tabel_f3_field10_ref", "x9",
+		"tabel_f3_field11", "penjual",
+		"tabel_f3_field11_ref", "users.nope",
+	))
+}
+
+func TestRelations(t *testing.T) {
+	s := relationsTestSchema()
+
+	type relation struct {
+		field, target, toField string
+		declared               bool
+	}
+	want := []relation{
+		{"id_user", "users", "id", false},
+		{"theme_id", "ot_themes", "id_theme", false},
+		{"id_category", "categories", "id_category", false},
+		{"kasir", "users", "id", true},
+		{"email_pelanggan", "users", "email", true},
+		{"pembeli", "pelanggan", "id_pelanggan", true},
+	}
+
+	var got []relation
+	for _, r := range s.Relations() {
+		if r.Table.Name != "transaksi" {
+			t.Errorf("unexpected relation from %s.%s", r.Table.Name, r.Field.Name)
+			continue
+		}
+		got = append(got, relation{r.Field.Name, r.Target.Name, r.ToField.Name, r.Declared})
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Relations = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("relation %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRelationLintIssues(t *testing.T) {
+	issues := map[string]LintIssue{}
+	for _, issue := range relationsTestSchema().Lint() {
+		if strings.HasPrefix(issue.Rule, "relation-") {
+			issues[issue.Key] = issue
+		}
+	}
+
+	tests := []struct {
+		key      string
+		rule     string
+		severity Severity
+	}{
+		{"tabel_f3_field5_ref", "relation-unresolved", SeverityInfo},
+		{"tabel_f3_field10_ref", "relation-unknown-table", SeverityError},
+		{"tabel_f3_field11_ref", "relation-unknown-field", SeverityError},
+	}
+	for _, tt := range tests {
+		issue, found := issues[tt.key]
+		if !found {
+			t.Errorf("no relation issue for %s", tt.key)
+			continue
+		}
+		if issue.Rule != tt.rule || issue.Severity != tt.severity {
+			t.Errorf("%s: %s %s, want %s %s", tt.key, issue.Severity, issue.Rule, tt.severity, tt.rule)
+		}
+		delete(issues, tt.key)
+	}
+	// Primary keys, "none" and resolved references are not reported
+	for key, issue := range issues {
+		t.Errorf("unexpected issue for %s: %s", key, issue.Message)
+	}
+}
+
+func TestInferTarget(t *testing.T) {
+	s := ParseSchema(environment(
+		"tabel_a1", "user",
+		"tabel_a2", "users",
+		"tabel_a3", "boxes",
+		"tabel_a4", "categories",
+		"tabel_a5", "ot_themes",
+		"tabel_a6", "ot_event",
+	))
+	tests := []struct {
+		stem, want string
+	}{
+		{"user", "user"},
+		{"box", "boxes"},
+		{"category", "categories"},
+		{"theme", "ot_themes"},
+		{"event", "ot_event"},
+		{"cabang", ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if target := s.inferTarget(tt.stem); target != nil {
+			got = target.Name
+		}
+		if got != tt.want {
+			t.Errorf("inferTarget(%q) = %q, want %q", tt.stem, got, tt.want)
+		}
+	}
+}
+
+func TestWriteERD(t *testing.T) {
+	s := relationsTestSchema()
+	tests := []struct {
+		format string
+		groups []string
+		want   []string
+		absent []string
+	}{
+		{
+			format: "mermaid",
+			want:   []string{"erDiagram", "users ||--o{ transaksi : \"kasir\"", "id id_user FK"},
+		},
+		{
+			format: "dot",
+			want:   []string{"digraph omnitags {", `"transaksi":"email_pelanggan" -> "users":"email";`},
+		},
+		{
+			format: "mermaid",
+			groups: []string{"f"},
+			want:   []string{"pelanggan ||--o{ transaksi : \"pembeli\""},
+			absent: []string{"users ||--o{", "users {"},
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := s.WriteERD(&buf, tt.format, tt.groups...); err != nil {
+			t.Fatalf("%s %v: %v", tt.format, tt.groups, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s %v has no %s:\n%s", tt.format, tt.groups, want, buf.String())
+			}
+		}
+		for _, absent := range tt.absent {
+			if strings.Contains(buf.String(), absent) {
+				t.Errorf("%s %v has %s:\n%s", tt.format, tt.groups, absent, buf.String())
+			}
+		}
+	}
+
+	if err := s.WriteERD(&bytes.Buffer{}, "svg"); err == nil {
+		t.Error("WriteERD accepts the unknown format svg")
+	}
+	if err := s.WriteERD(&bytes.Buffer{}, "dot", "z"); err == nil {
+		t.Error("WriteERD accepts the unknown group z")
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+// ReverseLookup returns every key whose value is value, in file order.
+// Common column names such as id or email resolve to one key per table,
+// unlike Reverse which only keeps the last of them.
+func (c *Omnitags) ReverseLookup(value string) []string {
+	keys := c.ReverseKeys[value]
+	return append([]string(nil), keys...)
+}
+
+// ReverseInTable returns the field key of column within table.
+// The table may be given as a code ("c2"), a key ("tabel_c2") or a table name ("users").
+func (c *Omnitags) ReverseInTable(table, column string) (string, bool) {
+	t := c.FindTable(table)
+	if t == nil {
+		return "", false
+	}
+	f := t.Field(column)
+	if f == nil {
+		return "", false
+	}
+	return f.Key, true
+}
+
+// FindTable resolves a table code ("c2"), key ("tabel_c2") or name ("users"), or returns nil
+func (c *Omnitags) FindTable(table string) *Table {
+	return c.Schema.findTable(table)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReverseMapping(t *testing.T) {
+	c := loadTestOmnitags(environment(
+		"tabel_c1", "roles",
+		"tabel_c1_field1", "id",
+		"tabel_c1_field2", "name",
+		"tabel_c2", "users",
+		"tabel_c2_alias", "Users",
+		"tabel_c2_field1", "id",
+		"tabel_c2_field2", "email",
+		"tabel_f3", "transaksi",
+		"tabel_f3_field1", "id",
+		"tabel_f3_field2", "email",
+	))
+
+	t.Run("ReverseLookup", func(t *testing.T) {
+		tests := []struct {
+			value string
+			want  []string
+		}{
+			{"id", []string{"tabel_c1_field1", "tabel_c2_field1", "tabel_f3_field1"}},
+			{"email", []string{"tabel_c2_field2", "tabel_f3_field2"}},
+			{"users", []string{"tabel_c2"}},
+			{"Users", []string{"tabel_c2_alias"}},
+			{"nothing", nil},
+		}
+		for _, tt := range tests {
+			if got := c.ReverseLookup(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReverseLookup(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		}
+
+		// The result is a copy, callers cannot change the snapshot
+		c.ReverseLookup("id")[0] = "changed"
+		if got := c.ReverseLookup("id")[0]; got != "tabel_c1_field1" {
+			t.Errorf("ReverseLookup(id)[0] = %q after changing a result", got)
+		}
+	})
+
+	t.Run("Reverse keeps the last key", func(t *testing.T) {
+		if got := c.Reverse["id_realname"]; got != "tabel_f3_field1" {
+			t.Errorf("Reverse[id_realname] = %q, want tabel_f3_field1", got)
+		}
+		if got := c.Reverse["users_realname"]; got != "tabel_c2" {
+			t.Errorf("Reverse[users_realname] = %q, want tabel_c2", got)
+		}
+	})
+
+	t.Run("ReverseInTable", func(t *testing.T) {
+		tests := []struct {
+			table, column string
+			want          string
+			found         bool
+		}{
+			{"c2", "email", "tabel_c2_field2", true},
+			{"tabel_f3", "email", "tabel_f3_field2", true},
+			{"users", "id", "tabel_c2_field1", true},
+			{"roles", "email", "", false},
+			{"nothing", "id", "", false},
+		}
+		for _, tt := range tests {
+			got, found := c.ReverseInTable(tt.table, tt.column)
+			if got != tt.want || found != tt.found {
+				t.Errorf("ReverseInTable(%q, %q) = %q, %v, want %q, %v", tt.table, tt.column, got, found, tt.want, tt.found)
+			}
+		}
+	})
+}
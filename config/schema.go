@@ -0,0 +1,287 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnumValue is one allowed value of a field, e.g. tabel_f3_field5_value1=debit.
+type EnumValue struct {
+	Key   string `json:"key"`
+	Index int    `json:"index"`
+	Value string `json:"value"`
+	Alias string `json:"alias"`
+}
+
+// Field is a single column of a table, e.g. tabel_f3_field5=metode.
+type Field struct {
+	Key   string `json:"key"`
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Alias string `json:"alias"`
+	// Ref declares the table the field references, see Relations
+	Ref    string      `json:"ref,omitempty"`
+	Values []EnumValue `json:"values,omitempty"`
+}
+
+// Table is a table described by the environment file, e.g. tabel_f3=transaksi.
+type Table struct {
+	Key    string   `json:"key"`
+	Group  string   `json:"group"`
+	Index  int      `json:"index"`
+	Name   string   `json:"name"`
+	Alias  string   `json:"alias"`
+	Alias2 string   `json:"alias2,omitempty"`
+	Fields []*Field `json:"fields"`
+}
+
+// Schema is the structured view of the tabel_* entries of an environment file.
+type Schema struct {
+	Name     string            `json:"name"`
+	Tables   []*Table          `json:"tables"`
+	Settings map[string]string `json:"settings"`
+	// GroupInfo holds the labels and ordering declared for table groups
+	GroupInfo map[string]GroupInfo `json:"group_info,omitempty"`
+	// Unparsed lists tabel_* keys that do not follow the naming convention
+	Unparsed []string `json:"unparsed,omitempty"`
+	// DuplicateKeys lists keys that appear more than once, the last value wins
+	DuplicateKeys []string `json:"duplicate_keys,omitempty"`
+	// VariableIssues lists {{references}} that could not be expanded
+	VariableIssues []VariableIssue `json:"variable_issues,omitempty"`
+}
+
+// schemaKeyPattern matches tabel_<group><n>[_alias|_alias2|_field<m>[_alias|_ref|_value<k>[_alias]]]
+var schemaKeyPattern = regexp.MustCompile(`^tabel_([a-z]+)(\d+)(?:_(alias2?)|_field(\d+)(?:_(alias|ref)|_value(\d+)(_alias)?)?)?$`)
+
+// isMetadataKey reports whether key is a group label or order or a field reference,
+// e.g. tabel_b_alias or tabel_e3_field5_ref, which get no reverse lookups or naming convention entries
+func isMetadataKey(key string) bool {
+	if groupKeyPattern.MatchString(key) {
+		return true
+	}
+	m := schemaKeyPattern.FindStringSubmatch(key)
+	return m != nil && m[5] == "ref"
+}
+
+// NewSchema returns an empty schema
+func NewSchema() *Schema {
+	return &Schema{
+		Tables:    []*Table{},
+		Settings:  make(map[string]string),
+		GroupInfo: make(map[string]GroupInfo),
+	}
+}
+
+// ParseSchema builds a Schema from the parsed Postman environment JSON, skipping disabled and secret entries
+func ParseSchema(data map[string]interface{}) *Schema {
+	s := NewSchema()
+	if name, ok := data["name"].(string); ok {
+		s.Name = name
+	}
+
+	tables := make(map[string]*Table)
+	seen := make(map[string]bool)
+	entries, issues := resolvedEntries(data)
+	s.VariableIssues = issues
+	for _, entry := range entries {
+		if !entry.Enabled || entry.Secret() {
+			continue
+		}
+		if seen[entry.Key] {
+			s.DuplicateKeys = append(s.DuplicateKeys, entry.Key)
+		}
+		seen[entry.Key] = true
+		s.addEntry(tables, entry.Key, entry.Value)
+	}
+
+	s.sortTables()
+	return s
+}
+
+// addEntry places a single key-value pair into the schema
+func (s *Schema) addEntry(tables map[string]*Table, key, value string) {
+	if s.addGroupEntry(key, value) {
+		return
+	}
+	m := schemaKeyPattern.FindStringSubmatch(key)
+	if m == nil {
+		if strings.HasPrefix(key, "tabel_") {
+			s.Unparsed = append(s.Unparsed, key)
+			return
+		}
+		s.Settings[key] = value
+		return
+	}
+
+	group, tableIndex := m[1], atoi(m[2])
+	code := fmt.Sprintf("%s%d", group, tableIndex)
+	table, exists := tables[code]
+	if !exists {
+		table = &Table{Key: "tabel_" + code, Group: group, Index: tableIndex, Fields: []*Field{}}
+		tables[code] = table
+		s.Tables = append(s.Tables, table)
+	}
+
+	switch {
+	case m[3] == "alias":
+		table.Alias = value
+	case m[3] == "alias2":
+		table.Alias2 = value
+	case m[4] == "":
+		table.Name = value
+	default:
+		field := table.fieldAt(atoi(m[4]))
+		switch {
+		case m[5] == "alias":
+			field.Alias = value
+		case m[5] == "ref":
+			field.Ref = value
+		case m[6] == "":
+			field.Name = value
+		default:
+			enum := field.valueAt(atoi(m[6]))
+			if m[7] != "" {
+				enum.Alias = value
+			} else {
+				enum.Value = value
+			}
+		}
+	}
+}
+
+// fieldAt returns the field with the given index, creating it if needed
+func (t *Table) fieldAt(index int) *Field {
+	for _, f := range t.Fields {
+		if f.Index == index {
+			return f
+		}
+	}
+	f := &Field{Key: fmt.Sprintf("%s_field%d", t.Key, index), Index: index}
+	t.Fields = append(t.Fields, f)
+	return f
+}
+
+// valueAt returns the enum value with the given index, creating it if needed
+func (f *Field) valueAt(index int) *EnumValue {
+	for i := range f.Values {
+		if f.Values[i].Index == index {
+			return &f.Values[i]
+		}
+	}
+	f.Values = append(f.Values, EnumValue{Key: fmt.Sprintf("%s_value%d", f.Key, index), Index: index})
+	return &f.Values[len(f.Values)-1]
+}
+
+// sortTables orders tables by group and index, fields and values by index
+func (s *Schema) sortTables() {
+	sort.SliceStable(s.Tables, func(i, j int) bool {
+		if s.Tables[i].Group != s.Tables[j].Group {
+			return s.Tables[i].Group < s.Tables[j].Group
+		}
+		return s.Tables[i].Index < s.Tables[j].Index
+	})
+	for _, t := range s.Tables {
+		sort.SliceStable(t.Fields, func(i, j int) bool { return t.Fields[i].Index < t.Fields[j].Index })
+		for _, f := range t.Fields {
+			sort.SliceStable(f.Values, func(i, j int) bool { return f.Values[i].Index < f.Values[j].Index })
+		}
+	}
+}
+
+// Code returns the short table code, e.g. "f3"
+func (t *Table) Code() string {
+	return fmt.Sprintf("%s%d", t.Group, t.Index)
+}
+
+// Field returns the field with the given column name, or nil
+func (t *Table) Field(name string) *Field {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the "id" field, falling back to the first field, or nil for a table without fields
+func (t *Table) PrimaryKey() *Field {
+	if f := t.Field("id"); f != nil {
+		return f
+	}
+	if len(t.Fields) > 0 {
+		return t.Fields[0]
+	}
+	return nil
+}
+
+// FieldNames returns the column names in field order
+func (t *Table) FieldNames() []string {
+	names := make([]string, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// Table returns the table with the given code (e.g. "a1"), or nil
+func (s *Schema) Table(code string) *Table {
+	for _, t := range s.Tables {
+		if t.Code() == code {
+			return t
+		}
+	}
+	return nil
+}
+
+// TableByName returns the table with the given database name, or nil
+func (s *Schema) TableByName(name string) *Table {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Groups returns the names of the groups in TableGroups order
+func (s *Schema) Groups() []string {
+	var groups []string
+	for _, g := range s.TableGroups() {
+		groups = append(groups, g.Name)
+	}
+	return groups
+}
+
+// Validate checks the invariants every consumer of the schema relies on
+func (s *Schema) Validate() error {
+	if len(s.Tables) == 0 {
+		return fmt.Errorf("no tabel_* entries found")
+	}
+	if len(s.VariableIssues) > 0 {
+		return s.VariableIssues[0]
+	}
+	seen := make(map[string]string)
+	for _, t := range s.Tables {
+		if t.Name == "" {
+			return fmt.Errorf("%s has no table name", t.Key)
+		}
+		if other, exists := seen[t.Name]; exists {
+			return fmt.Errorf("%s and %s both use table name %q", other, t.Key, t.Name)
+		}
+		seen[t.Name] = t.Key
+	}
+	return nil
+}
+
+// HasValues reports whether the field declares enum values
+func (f *Field) HasValues() bool {
+	return len(f.Values) > 0
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSchemaKeyGrammar(t *testing.T) {
+	field := func(s *Schema, code string, index int) *Field {
+		if table := s.Table(code); table != nil {
+			for _, f := range table.Fields {
+				if f.Index == index {
+					return f
+				}
+			}
+		}
+		return &Field{}
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		got   func(s *Schema) interface{}
+		want  interface{}
+	}{
+		{"table name", "tabel_f3", "transaksi", func(s *Schema) interface{} { return s.Table("f3").Name }, "transaksi"},
+		{"table alias", "tabel_f3_alias", "Transaksi", func(s *Schema) interface{} { return s.Table("f3").Alias }, "Transaksi"},
+		{"table alias2", "tabel_f3_alias2", "Transactions", func(s *Schema) interface{} { return s.Table("f3").Alias2 }, "Transactions"},
+		{"field name", "tabel_f3_field2", "metode", func(s *Schema) interface{} { return field(s, "f3", 2).Name }, "metode"},
+		{"field key", "tabel_f3_field2", "metode", func(s *Schema) interface{} { return field(s, "f3", 2).Key }, "tabel_f3_field2"},
+		{"field alias", "tabel_f3_field2_alias", "Metode", func(s *Schema) interface{} { return field(s, "f3", 2).Alias }, "Metode"},
+		{"field reference", "tabel_f3_field2_ref", "c2", func(s *Schema) interface{} { return field(s, "f3", 2).Ref }, "c2"},
+		{"enum value", "tabel_f3_field2_value1", "cash", func(s *Schema) interface{} { return field(s, "f3", 2).Values[0].Value }, "cash"},
+		{"enum value alias", "tabel_f3_field2_value1_alias", "Tunai", func(s *Schema) interface{} { return field(s, "f3", 2).Values[0].Alias }, "Tunai"},
+		{"multi-letter group", "tabel_ab12", "ot_x", func(s *Schema) interface{} { return s.Table("ab12").Group }, "ab"},
+		{"group label", "tabel_f_alias", "Finance", func(s *Schema) interface{} { return s.GroupInfo["f"].Label }, "Finance"},
+		{"group order", "tabel_f_order", "3", func(s *Schema) interface{} { return s.GroupInfo["f"].Order }, 3},
+		{"invalid group order", "tabel_f_order", "first", func(s *Schema) interface{} { return s.Unparsed }, []string{"tabel_f_order"}},
+		{"unknown suffix", "tabel_f3_field2_label", "x", func(s *Schema) interface{} { return s.Unparsed }, []string{"tabel_f3_field2_label"}},
+		{"reference on an enum value", "tabel_f3_field2_value1_ref", "x", func(s *Schema) interface{} { return s.Unparsed }, []string{"tabel_f3_field2_value1_ref"}},
+		{"table without index", "tabel_f", "x", func(s *Schema) interface{} { return s.Unparsed }, []string{"tabel_f"}},
+		{"setting", "view_sections", "4", func(s *Schema) interface{} { return s.Settings["view_sections"] }, "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ParseSchema(environment(tt.key, tt.value))
+			if got := tt.got(s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s=%s: got %#v, want %#v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchemaOrderAndEntries(t *testing.T) {
+	data := environment(
+		"tabel_f3_field2", "metode",
+		"tabel_f3", "transaksi",
+		"tabel_c2", "users",
+		"tabel_f3_field1", "id",
+		"tabel_f3_field2_value2", "transfer",
+		"tabel_f3_field2_value1", "cash",
+		"tabel_f1", "pelanggan",
+		"tabel_c2", "members",
+	)
+	withEntry(data, "tabel_f4", "hidden", "default", false)
+	withEntry(data, "tabel_f5", "secret", "secret", true)
+	s := ParseSchema(data)
+
+	var codes []string
+	for _, table := range s.Tables {
+		codes = append(codes, table.Code())
+	}
+	if want := []string{"c2", "f1", "f3"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("tables = %v, want %v; disabled and secret entries are skipped", codes, want)
+	}
+	if got := s.Table("f3").FieldNames(); !reflect.DeepEqual(got, []string{"id", "metode"}) {
+		t.Errorf("fields = %v, want [id metode]", got)
+	}
+	if got := s.Table("f3").Field("metode").EnumValues(); !reflect.DeepEqual(got, []string{"cash", "transfer"}) {
+		t.Errorf("enum values = %v, want [cash transfer]", got)
+	}
+	if got := s.Table("c2").Name; got != "members" {
+		t.Errorf("duplicate key resolves to %q, want the last value members", got)
+	}
+	if !reflect.DeepEqual(s.DuplicateKeys, []string{"tabel_c2"}) {
+		t.Errorf("DuplicateKeys = %v, want [tabel_c2]", s.DuplicateKeys)
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// Redacted replaces secret values wherever the config is printed or serialized
+const Redacted = "[REDACTED]"
+
+// Secrets holds the values of entries marked `type: secret`.
+// They are kept out of Aliases and the other derived maps, and are redacted when printed, logged or marshaled.
+type Secrets struct {
+	values map[string]string
+}
+
+// NewSecrets returns an empty secret store
+func NewSecrets() Secrets {
+	return Secrets{values: make(map[string]string)}
+}
+
+func (s Secrets) set(key, value string) {
+	s.values[key] = value
+}
+
+// Get returns the secret value stored under key
+func (s Secrets) Get(key string) (string, bool) {
+	value, exists := s.values[key]
+	return value, exists
+}
+
+// Keys returns the secret keys in sorted order
+func (s Secrets) Keys() []string {
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Len returns the number of secrets
+func (s Secrets) Len() int {
+	return len(s.values)
+}
+
+// String implements fmt.Stringer without revealing any value
+func (s Secrets) String() string {
+	return fmt.Sprintf("Secrets(%d %s)", len(s.values), Redacted)
+}
+
+// GoString implements fmt.GoStringer without revealing any value
+func (s Secrets) GoString() string {
+	return s.String()
+}
+
+// LogValue implements slog.LogValuer without revealing any value
+func (s Secrets) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON lists the secret keys with redacted values
+func (s Secrets) MarshalJSON() ([]byte, error) {
+	out := make(map[string]string, len(s.values))
+	for key := range s.values {
+		out[key] = Redacted
+	}
+	return json.Marshal(out)
+}
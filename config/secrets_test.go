@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecrets(t *testing.T) {
+	data := environment("base_url", "http://localhost", "tabel_a1", "ot_website")
+	withEntry(data, "db_password", "hunter2", "secret", true)
+	withEntry(data, "old_password", "letmein", "secret", false)
+	c := loadTestOmnitags(data)
+
+	if value, ok := c.Secrets.Get("db_password"); !ok || value != "hunter2" {
+		t.Errorf("Secrets.Get(db_password) = %q, %v, want hunter2", value, ok)
+	}
+	if _, ok := c.Secrets.Get("old_password"); ok {
+		t.Error("a disabled secret is stored")
+	}
+	if _, ok := c.Aliases["db_password"]; ok {
+		t.Error("the secret reached Aliases")
+	}
+	if _, err := c.Lookup("db_password"); err == nil || !strings.Contains(err.Error(), "read it through Secrets") {
+		t.Errorf("Lookup(db_password) = %v, want a secret error", err)
+	}
+	if keys := c.Secrets.Keys(); len(keys) != 1 || keys[0] != "db_password" || c.Secrets.Len() != 1 {
+		t.Errorf("Keys = %v, want [db_password]", keys)
+	}
+
+	marshaled, err := json.Marshal(c.Secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logged bytes.Buffer
+	slog.New(slog.NewTextHandler(&logged, nil)).Info("config", "secrets", c.Secrets)
+	outputs := map[string]string{
+		"%v":   fmt.Sprintf("%v", c.Secrets),
+		"%#v":  fmt.Sprintf("%#v", c.Secrets),
+		"%+v":  fmt.Sprintf("%+v", c),
+		"json": string(marshaled),
+		"slog": logged.String(),
+	}
+	for format, out := range outputs {
+		if strings.Contains(out, "hunter2") {
+			t.Errorf("%s output reveals the secret: %s", format, out)
+		}
+	}
+	if string(marshaled) != `{"db_password":"[REDACTED]"}` {
+		t.Errorf("json = %s, want the key with a redacted value", marshaled)
+	}
+}
+
+func TestSecretsAreRedactedFromOutputs(t *testing.T) {
+	data := environment("base_url", "http://localhost", "tabel_a1", "ot_website", "tabel_a1_field1", "id")
+	withEntry(data, "db_password", "hunter2", "secret", true)
+	c := loadTestOmnitags(data)
+
+	outputs := map[string]string{}
+	for _, format := range ExportFormats() {
+		outputs["export "+format] = export(t, format, c)
+	}
+	env, err := ParseSchema(data).MarshalEnvironment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs["environment"] = string(env)
+	derived, err := json.Marshal(c.Derived("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs["derived"] = string(derived)
+
+	for name, out := range outputs {
+		if strings.Contains(out, "hunter2") {
+			t.Errorf("%s reveals the secret value:\n%s", name, out)
+		}
+		if name == "environment" && strings.Contains(out, "db_password") {
+			t.Errorf("environment keeps the secret entry:\n%s", out)
+		}
+	}
+}
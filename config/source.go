@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultOmnitagsFile is used when no environment file is configured
+const DefaultOmnitagsFile = "app.postman_environment.json"
+
+// environmentFileSuffix is the suffix Postman gives exported environments
+const environmentFileSuffix = ".postman_environment.json"
+
+// OmnitagsSource describes which Postman environment the Omnitags config is loaded from
+type OmnitagsSource struct {
+	// Path is an environment file, or a directory holding several of them
+	Path string
+	// Environment selects a named environment (e.g. dev, staging, prod) by its Postman name or file prefix
+	Environment string
+	// NamingFile overrides the conventions used to derive view names, input names, titles and so on
+	NamingFile string
+}
+
+// OmnitagsSourceFromEnv reads the source from the OMNITAGSFILE, OMNITAGSENV and OMNITAGSNAMING environment variables
+func OmnitagsSourceFromEnv() OmnitagsSource {
+	return OmnitagsSource{
+		Path:        getEnv("OMNITAGSFILE", DefaultOmnitagsFile),
+		Environment: os.Getenv("OMNITAGSENV"),
+		NamingFile:  os.Getenv("OMNITAGSNAMING"),
+	}
+}
+
+// Resolve returns the environment file the source points to
+func (s OmnitagsSource) Resolve() (string, error) {
+	path := s.Path
+	if path == "" {
+		path = DefaultOmnitagsFile
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("omnitags source %q: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		if s.Environment == "" || environmentMatches(path, s.Environment) {
+			return path, nil
+		}
+		// Look for the named environment next to the configured file
+		path = filepath.Dir(path)
+	}
+
+	candidates, err := filepath.Glob(filepath.Join(path, "*"+environmentFileSuffix))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(candidates)
+
+	if s.Environment == "" {
+		if len(candidates) == 1 {
+			return candidates[0], nil
+		}
+		return "", fmt.Errorf("omnitags source %q holds %d environment files, set OMNITAGSENV to pick one", path, len(candidates))
+	}
+
+	for _, candidate := range candidates {
+		if environmentMatches(candidate, s.Environment) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("omnitags environment %q not found in %q", s.Environment, path)
+}
+
+// environmentMatches reports whether the file is named after the environment or declares it as its Postman name
+func environmentMatches(path, environment string) bool {
+	base := strings.TrimSuffix(filepath.Base(path), environmentFileSuffix)
+	if strings.EqualFold(base, environment) {
+		return true
+	}
+
+	data, err := ReadEnvironmentFile(path)
+	if err != nil {
+		return false
+	}
+	name, _ := data["name"].(string)
+	return strings.EqualFold(name, environment)
+}
+
+// ReadEnvironment resolves the source and parses the environment file it points to
+func (s OmnitagsSource) ReadEnvironment() (map[string]interface{}, error) {
+	path, err := s.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ReadEnvironmentFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading omnitags file %q: %w", path, err)
+	}
+	return data, nil
+}
+
+// LoadOmnitags builds a new Omnitags config from the source
+func LoadOmnitags(s OmnitagsSource) (*Omnitags, error) {
+	data, err := s.ReadEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewConfig()
+	if s.NamingFile != "" {
+		if c.Naming, err = LoadNamingConvention(s.NamingFile); err != nil {
+			return nil, err
+		}
+	}
+	c.LoadData(data)
+	return c, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return fallback
+}
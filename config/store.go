@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OmnitagsStore holds the current Omnitags snapshot and swaps it atomically on reload.
+// A snapshot returned by Get is never modified afterwards, so readers always see consistent maps.
+type OmnitagsStore struct {
+	source  OmnitagsSource
+	current atomic.Pointer[Omnitags]
+
+	mu          sync.Mutex // serializes reloads and guards the fields below
+	subscribers []func(*Omnitags)
+	seenPath    string // last file version seen, whether it loaded or not
+	seenModTime time.Time
+	seenSize    int64
+
+	// notifyMu runs the subscribers of one reload at a time, in the order the snapshots were stored
+	notifyMu sync.Mutex
+}
+
+// NewOmnitagsStore loads the source once and returns a store holding the result
+func NewOmnitagsStore(source OmnitagsSource) (*OmnitagsStore, error) {
+	s := &OmnitagsStore{source: source}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the current snapshot, it must be treated as read-only
+func (s *OmnitagsStore) Get() *Omnitags {
+	return s.current.Load()
+}
+
+// Subscribe registers a callback that runs with the new snapshot after every successful reload.
+// Callbacks run one reload at a time, so the last call always carries the current snapshot; they must not reload the store.
+func (s *OmnitagsStore) Subscribe(fn func(*Omnitags)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Reload rebuilds the config from the source, validates it and swaps it in.
+// On failure the previous snapshot stays in place.
+func (s *OmnitagsStore) Reload() error {
+	_, _, err := s.reload(false)
+	return err
+}
+
+// reload loads the source and notifies subscribers, when onlyChanged is set
+// it skips file versions that were already seen. It returns the file the source resolved to.
+func (s *OmnitagsStore) reload(onlyChanged bool) (string, bool, error) {
+	s.mu.Lock()
+	path, err := s.source.Resolve()
+	if err != nil {
+		s.mu.Unlock()
+		return "", false, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		s.mu.Unlock()
+		return path, false, err
+	}
+	if onlyChanged && path == s.seenPath && info.ModTime().Equal(s.seenModTime) && info.Size() == s.seenSize {
+		s.mu.Unlock()
+		return path, false, nil
+	}
+	s.seenPath, s.seenModTime, s.seenSize = path, info.ModTime(), info.Size()
+
+	source := s.source
+	source.Path, source.Environment = path, ""
+	c, err := LoadOmnitags(source)
+	if err == nil {
+		if verr := c.Validate(); verr != nil {
+			err = fmt.Errorf("omnitags file %q is invalid: %w", path, verr)
+		}
+	}
+	if err != nil {
+		s.mu.Unlock()
+		return path, true, err
+	}
+
+	s.current.Store(c)
+	subscribers := append([]func(*Omnitags){}, s.subscribers...)
+	// Taken before mu is released so a later reload cannot notify first
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(c)
+	}
+	return path, true, nil
+}
+
+// Watch polls the source every interval and reloads when the file changes, until ctx is done.
+// Reload errors are logged and the previous snapshot is kept.
+func (s *OmnitagsStore) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// The source may be a directory or name an environment, log the file it resolved to
+			path, reloaded, err := s.reload(true)
+			if err != nil {
+				log.Printf("Error reloading Omnitags configuration: %v", err)
+			} else if reloaded {
+				log.Printf("Omnitags configuration reloaded from %s", path)
+			}
+		}
+	}
+}
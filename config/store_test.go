@@ -0,0 +1,118 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOmnitagsStoreNotifiesInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.postman_environment.json")
+	if err := os.WriteFile(path, []byte(`{"name":"test","values":[{"key":"tabel_a1","value":"ot_website","enabled":true},{"key":"tabel_a1_field1","value":"id","enabled":true}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewOmnitagsStore(OmnitagsSource{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var last *Omnitags
+	store.Subscribe(func(c *Omnitags) {
+		// Give a concurrent reload the chance to overtake this notification
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		last = c
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.Reload(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if last != store.Get() {
+		t.Fatal("the last notified snapshot is not the current one")
+	}
+}
+
+// syncBuffer collects the log output of a watcher goroutine
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// eventually polls cond until it holds or a second has passed
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestOmnitagsStoreWatch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	table := func(name string) string {
+		return `{"name":"test","values":[{"key":"tabel_a1","value":"` + name + `","enabled":true},{"key":"tabel_a1_field1","value":"id","enabled":true}]}`
+	}
+	write("dev.postman_environment.json", table("dev_website"))
+	write("staging.postman_environment.json", table("ot_website"))
+
+	var logs syncBuffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	// The source is a directory, the watcher must name the file it picked
+	store, err := NewOmnitagsStore(OmnitagsSource{Path: dir, Environment: "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, 5*time.Millisecond)
+
+	write("staging.postman_environment.json", table("ot_website_baru"))
+	eventually(t, "the changed file", func() bool { return store.Get().Aliases["tabel_a1"] == "ot_website_baru" })
+	want := "Omnitags configuration reloaded from " + filepath.Join(dir, "staging.postman_environment.json")
+	eventually(t, "the reload log", func() bool { return strings.Contains(logs.String(), want) })
+
+	// An invalid file is reported and the running snapshot is kept
+	write("staging.postman_environment.json", `{"name":`)
+	eventually(t, "the reload error", func() bool { return strings.Contains(logs.String(), "Error reloading Omnitags configuration") })
+	if got := store.Get().Aliases["tabel_a1"]; got != "ot_website_baru" {
+		t.Errorf("tabel_a1 = %q after an invalid file, want the previous snapshot", got)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+// FieldKind is the storage type inferred for a field from its name and values
+type FieldKind string
+
+const (
+	KindID     FieldKind = "id"
+	KindInt    FieldKind = "int"
+	KindTime   FieldKind = "time"
+	KindEnum   FieldKind = "enum"
+	KindText   FieldKind = "text"
+	KindString FieldKind = "string"
+)
+
+// intFieldNames are columns known to hold whole numbers
+var intFieldNames = []string{"batch", "jlh", "year", "semester", "bayar", "harga", "jumlah"}
+
+// textFieldNames are columns known to hold long free text
+var textFieldNames = []string{"keterangan", "deskripsi", "description", "konten", "payload", "exception", "alamat", "address"}
+
+// sensitiveFieldNames are columns holding credentials, see Sensitive
+var sensitiveFieldNames = []string{"password", "token", "secret", "api_key"}
+
+// Sensitive reports whether the field holds credentials such as password or remember_token.
+// The generic routes never return, filter, search or write sensitive fields.
+func (f *Field) Sensitive() bool {
+	name := strings.ToLower(f.Name)
+	for _, sensitive := range sensitiveFieldNames {
+		if name == sensitive || strings.HasSuffix(name, "_"+sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Kind infers the storage type of the field, the environment file itself carries no types
+func (f *Field) Kind() FieldKind {
+	name := f.Name
+	switch {
+	case f.HasValues():
+		return KindEnum
+	case name == "id" || strings.HasPrefix(name, "id_") || strings.HasSuffix(name, "_id"):
+		return KindID
+	case strings.HasSuffix(name, "_at") || strings.HasPrefix(name, "tgl_") || strings.HasPrefix(name, "cek_"):
+		return KindTime
+	case strings.HasSuffix(name, "_count") || strings.HasSuffix(name, "_total") || util.Contains(name, intFieldNames):
+		return KindInt
+	case util.Contains(name, textFieldNames):
+		return KindText
+	}
+	return KindString
+}
+
+// EnumValues returns the non-empty allowed values of the field
+func (f *Field) EnumValues() []string {
+	var values []string
+	for _, v := range f.Values {
+		if v.Value != "" {
+			values = append(values, v.Value)
+		}
+	}
+	return values
+}
+
+// commonInitialisms are rendered upper-case in Go identifiers
+var commonInitialisms = map[string]bool{
+	"id": true, "url": true, "uuid": true, "ip": true, "api": true, "hp": true, "nik": true,
+}
+
+// goName converts a snake_case name into an exported Go identifier
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		lower := strings.ToLower(part)
+		if commonInitialisms[lower] {
+			b.WriteString(strings.ToUpper(lower))
+			continue
+		}
+		b.WriteString(strings.ToUpper(lower[:1]) + lower[1:])
+	}
+	if b.Len() == 0 {
+		return "Empty"
+	}
+	out := b.String()
+	if out[0] >= '0' && out[0] <= '9' {
+		out = "N" + out
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+// FieldError describes a payload value that breaks a constraint declared in the environment file
+type FieldError struct {
+	Field   string      `json:"field"`
+	Key     string      `json:"key"`
+	Alias   string      `json:"alias,omitempty"`
+	Param   string      `json:"param,omitempty"`
+	Value   interface{} `json:"value"`
+	Message string      `json:"message"`
+	Allowed []string    `json:"allowed,omitempty"`
+}
+
+// FieldErrors is the list of violations found in one payload
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		name := fe.Field
+		if fe.Param != "" {
+			name = fe.Param
+		}
+		messages = append(messages, name+" "+fe.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValidateEnums checks the payload values of enum fields against their tabel_*_field*_value* entries.
+// Null clears a column and is always accepted, keys that are not fields of the table are ignored.
+func (t *Table) ValidateEnums(payload map[string]interface{}) FieldErrors {
+	var errs FieldErrors
+	for name, value := range payload {
+		f := t.Field(name)
+		if f == nil || value == nil {
+			continue
+		}
+		allowed := f.EnumValues()
+		if len(allowed) == 0 {
+			continue
+		}
+
+		s, isString := value.(string)
+		if isString && util.Contains(s, allowed) {
+			continue
+		}
+		errs = append(errs, FieldError{
+			Field:   f.Name,
+			Key:     f.Key,
+			Alias:   f.Alias,
+			Value:   value,
+			Message: fmt.Sprintf("must be one of %s", strings.Join(allowed, ", ")),
+			Allowed: allowed,
+		})
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
@@ -2,11 +2,13 @@ package endpoint
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/middleware"
 	"github.com/khenjyjohnelson/golang-omnitags/model"
 	"github.com/khenjyjohnelson/golang-omnitags/util"
 	"gorm.io/gorm"
@@ -95,6 +97,10 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// Browser pages authenticate with the same token in an HttpOnly cookie
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookieName, tokenString, int(time.Hour.Seconds()), "/", "", gin.Mode() == gin.ReleaseMode, true)
+
 	// Return the token in a JSON response
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg:  "Login successful",
@@ -143,6 +149,9 @@ func Logout(c *gin.Context) {
 		return
 	}
 
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookieName, "", -1, "/", "", gin.Mode() == gin.ReleaseMode, true)
+
 	// Respond with a success message
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg: "Logout successful",
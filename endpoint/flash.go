@@ -0,0 +1,16 @@
+package endpoint
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+// ListFlashes returns and clears the flash messages set by earlier requests, for frontends that render them
+func ListFlashes(c *gin.Context) {
+	flashes := flash.ConsumeFlashes(c)
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Flash messages retrieved",
+		Data: map[string]interface{}{"total": len(flashes), "flashes": flashes},
+	})
+}
@@ -0,0 +1,397 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
+	"github.com/khenjyjohnelson/golang-omnitags/middleware"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RegisterOmnitagsRoutes mounts list/report/get/create/update/delete routes for the tables in the Omnitags schema.
+// Tables are resolved per request so a hot reload of the store takes effect without re-registering routes.
+func RegisterOmnitagsRoutes(rg *gin.RouterGroup, store *config.OmnitagsStore, access OmnitagsAccess) {
+	rg.GET("/:table", withOmnitagsTable(store, access, listOmnitagsRows))
+	rg.POST("/:table", withOmnitagsTable(store, access, createOmnitagsRow))
+	rg.GET("/:table/report", withOmnitagsTable(store, access, reportOmnitagsRows))
+	rg.GET("/:table/:id", withOmnitagsTable(store, access, getOmnitagsRow))
+	rg.PATCH("/:table/:id", withOmnitagsTable(store, access, updateOmnitagsRow))
+	rg.DELETE("/:table/:id", withOmnitagsTable(store, access, deleteOmnitagsRow))
+}
+
+// OmnitagsAccess limits the generic routes: every signed-in user may use the tables in Tables,
+// given by name, code or key; the other tables require one of AdminRoles.
+type OmnitagsAccess struct {
+	Tables     []string
+	AdminRoles []string
+}
+
+// OmnitagsAccessFromEnv reads the allowed tables from OMNITAGSTABLES, a comma-separated list,
+// and the admin roles from OMNITAGSADMINROLES. Without OMNITAGSTABLES only admins can use the generic routes.
+func OmnitagsAccessFromEnv() OmnitagsAccess {
+	access := OmnitagsAccess{AdminRoles: middleware.AdminRoles()}
+	for _, name := range strings.Split(os.Getenv("OMNITAGSTABLES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			access.Tables = append(access.Tables, name)
+		}
+	}
+	return access
+}
+
+// allows reports whether every signed-in user may use table
+func (a OmnitagsAccess) allows(table *config.Table) bool {
+	for _, name := range a.Tables {
+		if name == table.Name || name == table.Code() || name == table.Key {
+			return true
+		}
+	}
+	return false
+}
+
+// connectOmnitagsDB opens the database the generic routes work on, tests swap it for SQLite
+var connectOmnitagsDB = config.ConnectMySQL
+
+// omnitagsSnapshotKey stores the config snapshot a request was resolved against in the gin context
+const omnitagsSnapshotKey = "omnitags"
+
+// withOmnitagsTable resolves the :table path parameter against the current snapshot and checks access to it
+func withOmnitagsTable(store *config.OmnitagsStore, access OmnitagsAccess, handler func(*gin.Context, *config.Table)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("table")
+		omnitags := store.Get()
+		c.Set(omnitagsSnapshotKey, omnitags)
+		table := omnitags.Schema.TableByName(name)
+		if table == nil || table.PrimaryKey() == nil {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "Table not found",
+				Err: fmt.Errorf("table %q is not described by omnitags", name),
+			})
+			return
+		}
+		if !access.allows(table) && !middleware.HasRole(c, access.AdminRoles) {
+			util.CallForbidden(c, util.APIErrorParams{
+				Msg: fmt.Sprintf("%s is restricted to administrators", table.Alias),
+				Err: fmt.Errorf("table %q is not in OMNITAGSTABLES", name),
+			})
+			return
+		}
+		handler(c, table)
+	}
+}
+
+func fetchOmnitagsRows(table *config.Table, filters []clause.Expression, limit, offset int, keyword, groupByDate string) ([]map[string]interface{}, int64, error) {
+	var rows []map[string]interface{}
+	var total int64
+
+	db, err := connectOmnitagsDB()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := db.Table(table.Name)
+	if keyword != "" {
+		var likes []clause.Expression
+		for _, f := range table.Fields {
+			if f.Name != "" && !f.Sensitive() {
+				likes = append(likes, config.Contains{Column: clause.Column{Name: f.Name}, Value: keyword})
+			}
+		}
+		// A table without searchable fields ignores the keyword
+		if len(likes) > 0 {
+			query = query.Where(clause.Or(likes...))
+		}
+	}
+	if len(filters) > 0 {
+		query = query.Where(clause.And(filters...))
+	}
+	if table.Field("created_at") != nil {
+		query = applyGroupByDateFilter(query, groupByDate)
+	}
+
+	// The total counts every filtered row, not only the requested page
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: table.PrimaryKey().Name}})
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	for _, row := range rows {
+		redactOmnitagsRow(table, row)
+	}
+	return rows, total, nil
+}
+
+func listOmnitagsRows(c *gin.Context, table *config.Table) {
+	limit, offset, keyword, groupByDate := parseQueryParams(c)
+
+	omnitags := c.MustGet(omnitagsSnapshotKey).(*config.Omnitags)
+	filters, errs := omnitags.BuildFilter(table, c.Request.URL.Query())
+	if len(errs) > 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg:  fmt.Sprintf("Invalid filter for %s", table.Alias),
+			Err:  errs,
+			Data: map[string]interface{}{"errors": errs},
+		})
+		return
+	}
+
+	rows, total, err := fetchOmnitagsRows(table, filters, limit, offset, keyword, groupByDate)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to retrieve %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s retrieved", table.Alias),
+		Data: map[string]interface{}{"total": total, table.Name: rows},
+	})
+}
+
+// bindOmnitagsPayload binds the request body and rejects keys that are not fields of the table
+// and values outside the enum values declared for a field
+func bindOmnitagsPayload(c *gin.Context, table *config.Table) (map[string]interface{}, error) {
+	payload := map[string]interface{}{}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return nil, err
+	}
+
+	var unknown, sensitive []string
+	for key := range payload {
+		switch f := table.Field(key); {
+		case f == nil:
+			unknown = append(unknown, key)
+		case f.Sensitive():
+			sensitive = append(sensitive, key)
+		}
+	}
+	if len(sensitive) > 0 {
+		sort.Strings(sensitive)
+		err := fmt.Errorf("fields cannot be written here: %v", sensitive)
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Payload contains sensitive fields of %s", table.Alias),
+			Err: err,
+		})
+		return nil, err
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		err := fmt.Errorf("unknown fields: %v", unknown)
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Payload contains fields that are not part of %s", table.Alias),
+			Err: err,
+		})
+		return nil, err
+	}
+
+	if errs := table.ValidateEnums(payload); len(errs) > 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg:  fmt.Sprintf("Payload contains values that are not allowed for %s", table.Alias),
+			Err:  errs,
+			Data: map[string]interface{}{"errors": errs},
+		})
+		return nil, errs
+	}
+	return payload, nil
+}
+
+// getOmnitagsRowByID looks up the row addressed by the :id path parameter
+func getOmnitagsRowByID(c *gin.Context, table *config.Table) (*gorm.DB, map[string]interface{}, error) {
+	id := c.Param("id")
+	if id == "" {
+		err := fmt.Errorf("%s ID is required", table.Name)
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Missing %s ID", table.Alias),
+			Err: err,
+		})
+		return nil, nil, err
+	}
+
+	db, err := connectOmnitagsDB()
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to connect to MySQL",
+			Err: err,
+		})
+		return nil, nil, err
+	}
+
+	row := map[string]interface{}{}
+	if err := db.Table(table.Name).Where(primaryKeyEq(table, id)).Take(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: fmt.Sprintf("%s not found", table.Alias),
+				Err: err,
+			})
+		} else {
+			util.CallServerError(c, util.APIErrorParams{
+				Msg: fmt.Sprintf("Failed to retrieve %s", table.Alias),
+				Err: err,
+			})
+		}
+		return nil, nil, err
+	}
+
+	redactOmnitagsRow(table, row)
+	return db, row, nil
+}
+
+// redactOmnitagsRow removes the sensitive fields of table from row, e.g. password
+func redactOmnitagsRow(table *config.Table, row map[string]interface{}) {
+	for _, f := range table.Fields {
+		if f.Sensitive() {
+			delete(row, f.Name)
+		}
+	}
+}
+
+func primaryKeyEq(table *config.Table, id interface{}) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: table.PrimaryKey().Name}, Value: id}
+}
+
+func getOmnitagsRow(c *gin.Context, table *config.Table) {
+	_, row, err := getOmnitagsRowByID(c, table)
+	if err != nil {
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s retrieved", table.Alias),
+		Data: row,
+	})
+}
+
+func createOmnitagsRow(c *gin.Context, table *config.Table) {
+	payload, err := bindOmnitagsPayload(c, table)
+	if err != nil {
+		return
+	}
+	if len(payload) == 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("%s payload is empty", table.Alias),
+			Err: fmt.Errorf("invalid payload"),
+		})
+		return
+	}
+
+	db, err := connectOmnitagsDB()
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to connect to MySQL",
+			Err: err,
+		})
+		return
+	}
+
+	if err := db.Table(table.Name).Create(payload).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to create %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	// GORM reports the auto-increment id of a map insert as @id
+	id, supplied := payload[table.PrimaryKey().Name]
+	if !supplied {
+		id = payload["@id"]
+	}
+	row := map[string]interface{}{}
+	if err := db.Table(table.Name).Where(primaryKeyEq(table, id)).Take(&row).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to retrieve the created %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+	redactOmnitagsRow(table, row)
+
+	if flash.Wanted(c) {
+		flash.SetFlash(c, table.Key, flash.Success)
+	}
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s created", table.Alias),
+		Data: row,
+	})
+}
+
+func updateOmnitagsRow(c *gin.Context, table *config.Table) {
+	payload, err := bindOmnitagsPayload(c, table)
+	if err != nil {
+		return
+	}
+	if len(payload) == 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("%s payload is empty", table.Alias),
+			Err: fmt.Errorf("invalid payload"),
+		})
+		return
+	}
+
+	db, row, err := getOmnitagsRowByID(c, table)
+	if err != nil {
+		return
+	}
+
+	if err := db.Table(table.Name).Where(primaryKeyEq(table, c.Param("id"))).Updates(payload).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to update %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	for key, value := range payload {
+		row[key] = value
+	}
+
+	if flash.Wanted(c) {
+		flash.SetFlash(c, table.Key, flash.Success)
+	}
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s updated", table.Alias),
+		Data: row,
+	})
+}
+
+func deleteOmnitagsRow(c *gin.Context, table *config.Table) {
+	db, _, err := getOmnitagsRowByID(c, table)
+	if err != nil {
+		return
+	}
+
+	if err := db.Table(table.Name).Where(primaryKeyEq(table, c.Param("id"))).Delete(map[string]interface{}{}).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to delete %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s deleted", table.Alias),
+		Data: nil,
+	})
+}
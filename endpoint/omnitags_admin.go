@@ -0,0 +1,212 @@
+package endpoint
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+// omnitagsEntry is one key of the environment file as the admin endpoints show it
+type omnitagsEntry struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// omnitagsTableSummary is a table without its fields, for listings
+type omnitagsTableSummary struct {
+	Key    string `json:"key"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Alias  string `json:"alias"`
+	Fields int    `json:"fields"`
+}
+
+// RegisterOmnitagsAdminRoutes mounts read-only routes for inspecting the resolved Omnitags configuration.
+// Secret values are always redacted.
+func RegisterOmnitagsAdminRoutes(rg *gin.RouterGroup, store *config.OmnitagsStore) {
+	rg.GET("", func(c *gin.Context) { omnitagsAdminSummary(c, store.Get()) })
+	rg.GET("/groups", func(c *gin.Context) { listOmnitagsAdminGroups(c, store.Get()) })
+	rg.GET("/relations", func(c *gin.Context) { listOmnitagsAdminRelations(c, store.Get()) })
+	rg.GET("/tables", func(c *gin.Context) { listOmnitagsAdminTables(c, store.Get()) })
+	rg.GET("/tables/:table", func(c *gin.Context) { getOmnitagsAdminTable(c, store.Get()) })
+	rg.GET("/keys", func(c *gin.Context) { listOmnitagsAdminKeys(c, store.Get()) })
+	rg.GET("/derived", func(c *gin.Context) { listOmnitagsAdminDerived(c, store.Get()) })
+}
+
+func omnitagsAdminSummary(c *gin.Context, omnitags *config.Omnitags) {
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Omnitags configuration retrieved",
+		Data: map[string]interface{}{
+			"name":     omnitags.Schema.Name,
+			"tables":   len(omnitags.Schema.Tables),
+			"keys":     len(omnitags.Aliases),
+			"secrets":  omnitags.Secrets.Len(),
+			"naming":   omnitags.Naming.Name,
+			"maps":     config.DerivedMapNames(),
+			"settings": omnitags.Schema.Settings,
+		},
+	})
+}
+
+// omnitagsGroupSummary is a table group with table summaries instead of full tables
+type omnitagsGroupSummary struct {
+	Name   string                 `json:"name"`
+	Label  string                 `json:"label"`
+	Order  int                    `json:"order,omitempty"`
+	Tables []omnitagsTableSummary `json:"tables"`
+}
+
+func listOmnitagsAdminGroups(c *gin.Context, omnitags *config.Omnitags) {
+	groups := []omnitagsGroupSummary{}
+	for _, g := range omnitags.Schema.TableGroups() {
+		group := omnitagsGroupSummary{Name: g.Name, Label: g.Label, Order: g.Order, Tables: []omnitagsTableSummary{}}
+		for _, t := range g.Tables {
+			group.Tables = append(group.Tables, omnitagsTableSummary{Key: t.Key, Code: t.Code(), Name: t.Name, Alias: t.Alias, Fields: len(t.Fields)})
+		}
+		groups = append(groups, group)
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Omnitags table groups retrieved",
+		Data: map[string]interface{}{"total": len(groups), "groups": groups},
+	})
+}
+
+// omnitagsRelation is a foreign key between two tables as the admin endpoints show it
+type omnitagsRelation struct {
+	Table       string `json:"table"`
+	Field       string `json:"field"`
+	Key         string `json:"key"`
+	TargetTable string `json:"target_table"`
+	TargetField string `json:"target_field"`
+	Declared    bool   `json:"declared"`
+}
+
+// listOmnitagsAdminRelations lists the inferred and declared relations, or draws them as
+// an ER diagram when format is mermaid or dot
+func listOmnitagsAdminRelations(c *gin.Context, omnitags *config.Omnitags) {
+	if format := c.Query("format"); format != "" {
+		var groups []string
+		if group := c.Query("group"); group != "" {
+			groups = strings.Split(group, ",")
+		}
+		var buf bytes.Buffer
+		if err := omnitags.Schema.WriteERD(&buf, format, groups...); err != nil {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Failed to draw the Omnitags diagram",
+				Err: err,
+			})
+			return
+		}
+		// Set explicitly, the CORS middleware already put application/json in the header
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	relations := []omnitagsRelation{}
+	for _, r := range omnitags.Schema.Relations() {
+		relations = append(relations, omnitagsRelation{
+			Table:       r.Table.Name,
+			Field:       r.Field.Name,
+			Key:         r.Field.Key,
+			TargetTable: r.Target.Name,
+			TargetField: r.ToField.Name,
+			Declared:    r.Declared,
+		})
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Omnitags relations retrieved",
+		Data: map[string]interface{}{"total": len(relations), "relations": relations},
+	})
+}
+
+func listOmnitagsAdminTables(c *gin.Context, omnitags *config.Omnitags) {
+	prefix := c.Query("prefix")
+	tables := []omnitagsTableSummary{}
+	for _, t := range omnitags.Schema.Tables {
+		if !strings.HasPrefix(t.Key, prefix) && !strings.HasPrefix(t.Name, prefix) {
+			continue
+		}
+		tables = append(tables, omnitagsTableSummary{Key: t.Key, Code: t.Code(), Name: t.Name, Alias: t.Alias, Fields: len(t.Fields)})
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Omnitags tables retrieved",
+		Data: map[string]interface{}{"total": len(tables), "tables": tables},
+	})
+}
+
+func getOmnitagsAdminTable(c *gin.Context, omnitags *config.Omnitags) {
+	name := c.Param("table")
+	table := omnitags.FindTable(name)
+	if table == nil {
+		util.CallErrorNotFound(c, util.APIErrorParams{
+			Msg: "Table not found",
+			Err: fmt.Errorf("table %q is not described by omnitags", name),
+		})
+		return
+	}
+
+	// Keep the values derived from the table and its fields, not from tabel_b10 when looking at tabel_b1
+	derived := []config.DerivedValue{}
+	for _, d := range omnitags.Derived("", table.Key) {
+		if d.Source == table.Key || strings.HasPrefix(d.Source, table.Key+"_") {
+			derived = append(derived, d)
+		}
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s retrieved", table.Alias),
+		Data: map[string]interface{}{"table": table, "derived": derived},
+	})
+}
+
+func listOmnitagsAdminKeys(c *gin.Context, omnitags *config.Omnitags) {
+	prefix := c.Query("prefix")
+	entries := []omnitagsEntry{}
+	for key, value := range omnitags.Aliases {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, omnitagsEntry{Key: key, Value: value})
+		}
+	}
+	for _, key := range omnitags.Secrets.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, omnitagsEntry{Key: key, Value: config.Redacted, Secret: true})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Omnitags keys retrieved",
+		Data: map[string]interface{}{"total": len(entries), "keys": entries},
+	})
+}
+
+func listOmnitagsAdminDerived(c *gin.Context, omnitags *config.Omnitags) {
+	mapName := c.Query("map")
+	if mapName != "" && !util.Contains(mapName, config.DerivedMapNames()) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Unknown derived map",
+			Err: fmt.Errorf("map %q is not one of %s", mapName, strings.Join(config.DerivedMapNames(), ", ")),
+		})
+		return
+	}
+
+	derived := omnitags.Derived(mapName, c.Query("prefix"))
+	if derived == nil {
+		derived = []config.DerivedValue{}
+	}
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Omnitags derived values retrieved",
+		Data: map[string]interface{}{"total": len(derived), "derived": derived},
+	})
+}
@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func TestOmnitagsAdminRoutesRedactSecrets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "test.postman_environment.json")
+	env := `{"name": "test", "values": [
+		{"key": "tabel_c2", "value": "users", "enabled": true},
+		{"key": "tabel_c2_field1", "value": "id", "enabled": true},
+		{"key": "db_password", "value": "hunter2", "type": "secret", "enabled": true}
+	]}`
+	if err := os.WriteFile(path, []byte(env), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := config.NewOmnitagsStore(config.OmnitagsSource{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	RegisterOmnitagsAdminRoutes(r.Group("/admin/omnitags"), store)
+
+	for _, target := range []string{"/admin/omnitags", "/admin/omnitags/keys", "/admin/omnitags/derived", "/admin/omnitags/tables/users"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", target, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "hunter2") {
+			t.Errorf("GET %s reveals the secret: %s", target, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/omnitags/keys?prefix=db_", nil))
+	if want := `{"key":"db_password","value":"[REDACTED]","secret":true}`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("GET /admin/omnitags/keys = %s, want %s", w.Body.String(), want)
+	}
+}
+
+func TestOmnitagsAdminGroups(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "test.postman_environment.json")
+	env := `{"name": "test", "values": [
+		{"key": "tabel_f_alias", "value": "Finance", "enabled": true},
+		{"key": "tabel_f_order", "value": "1", "enabled": true},
+		{"key": "tabel_c2", "value": "users", "enabled": true},
+		{"key": "tabel_c2_alias", "value": "Users", "enabled": true},
+		{"key": "tabel_c2_field1", "value": "id", "enabled": true},
+		{"key": "tabel_f3", "value": "transaksi", "enabled": true},
+		{"key": "tabel_f3_field1", "value": "id", "enabled": true},
+		{"key": "tabel_f3_field2", "value": "nama", "enabled": true}
+	]}`
+	if err := os.WriteFile(path, []byte(env), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := config.NewOmnitagsStore(config.OmnitagsSource{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	RegisterOmnitagsAdminRoutes(r.Group("/admin/omnitags"), store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/omnitags/groups", nil))
+	var res struct {
+		Data struct {
+			Total  int `json:"total"`
+			Groups []struct {
+				Name   string `json:"name"`
+				Label  string `json:"label"`
+				Order  int    `json:"order"`
+				Tables []struct {
+					Key    string `json:"key"`
+					Fields int    `json:"fields"`
+				} `json:"tables"`
+			} `json:"groups"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("GET /admin/omnitags/groups = %d %v\n%s", w.Code, err, w.Body.String())
+	}
+
+	groups := res.Data.Groups
+	if res.Data.Total != 2 || len(groups) != 2 {
+		t.Fatalf("groups = %+v, want f and c", groups)
+	}
+	if g := groups[0]; g.Name != "f" || g.Label != "Finance" || g.Order != 1 || len(g.Tables) != 1 || g.Tables[0].Key != "tabel_f3" || g.Tables[0].Fields != 2 {
+		t.Errorf("first group = %+v, want the ordered Finance group", g)
+	}
+	if g := groups[1]; g.Name != "c" || g.Label != "Group C" || g.Order != 0 || len(g.Tables) != 1 || g.Tables[0].Key != "tabel_c2" {
+		t.Errorf("second group = %+v, want the unordered group c", g)
+	}
+}
@@ -0,0 +1,263 @@
+package endpoint
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
+	"github.com/khenjyjohnelson/golang-omnitags/storage"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+// uploadTypes maps the MIME types accepted by default to the extension stored files get
+var uploadTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// defaultUploadMaxSize is used when UPLOADMAXSIZE is not set
+const defaultUploadMaxSize = 5 << 20
+
+// uploadMaxSize returns the largest accepted file in bytes, from UPLOADMAXSIZE
+func uploadMaxSize() int64 {
+	if size, err := strconv.ParseInt(os.Getenv("UPLOADMAXSIZE"), 10, 64); err == nil && size > 0 {
+		return size
+	}
+	return defaultUploadMaxSize
+}
+
+// uploadAllowed reports whether a sniffed MIME type may be stored, UPLOADTYPES narrows the defaults
+func uploadAllowed(contentType string) bool {
+	if _, known := uploadTypes[contentType]; !known {
+		return false
+	}
+	allowed := os.Getenv("UPLOADTYPES")
+	if allowed == "" {
+		return true
+	}
+	for _, t := range strings.Split(allowed, ",") {
+		if strings.TrimSpace(t) == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterOmnitagsFileRoutes mounts upload, list, download and delete routes for the files of every Omnitags record.
+// Files live in the VUploadPath directory of the table, one sub-directory per record.
+func RegisterOmnitagsFileRoutes(rg *gin.RouterGroup, store *config.OmnitagsStore, access OmnitagsAccess, files storage.Storage) {
+	rg.GET("/:table/:id/files", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { listOmnitagsFiles(c, table, files) }))
+	rg.POST("/:table/:id/files", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { uploadOmnitagsFile(c, table, files) }))
+	rg.GET("/:table/:id/files/:name", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { downloadOmnitagsFile(c, table, files) }))
+	rg.DELETE("/:table/:id/files/:name", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { deleteOmnitagsFile(c, table, files) }))
+}
+
+// omnitagsRecordDir checks that the record exists and returns its upload directory.
+// The directory is named after the stored primary key, not the raw path parameter.
+func omnitagsRecordDir(c *gin.Context, table *config.Table) (string, error) {
+	_, row, err := getOmnitagsRowByID(c, table)
+	if err != nil {
+		return "", err
+	}
+
+	omnitags := c.MustGet(omnitagsSnapshotKey).(*config.Omnitags)
+	uploadPath := omnitags.VUploadPath[table.Key]
+	if uploadPath == "" {
+		err := fmt.Errorf("%s has no upload path", table.Key)
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("%s does not accept files", table.Alias),
+			Err: err,
+		})
+		return "", err
+	}
+
+	id := row[table.PrimaryKey().Name]
+	if b, ok := id.([]byte); ok {
+		id = string(b)
+	}
+	return path.Join(filepath.ToSlash(uploadPath), fmt.Sprint(id)), nil
+}
+
+func listOmnitagsFiles(c *gin.Context, table *config.Table, files storage.Storage) {
+	dir, err := omnitagsRecordDir(c, table)
+	if err != nil {
+		return
+	}
+
+	objects, err := files.List(dir)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to list files",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s files retrieved", table.Alias),
+		Data: map[string]interface{}{"total": len(objects), "files": objects},
+	})
+}
+
+func uploadOmnitagsFile(c *gin.Context, table *config.Table, files storage.Storage) {
+	dir, err := omnitagsRecordDir(c, table)
+	if err != nil {
+		return
+	}
+	omnitags := c.MustGet(omnitagsSnapshotKey).(*config.Omnitags)
+	failed := omnitags.FlashMsg[table.Key]
+	if failed == "" {
+		failed = "Upload failed"
+	}
+	fail := func(call func(*gin.Context, util.APIErrorParams), err error) {
+		if flash.Wanted(c) {
+			flash.SetFlash(c, table.Key, flash.Error)
+		}
+		call(c, util.APIErrorParams{
+			Msg: failed,
+			Err: err,
+		})
+	}
+
+	// Leave room for the multipart envelope, the file itself is checked below
+	maxSize := uploadMaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		fail(util.CallUserError, fmt.Errorf("multipart field \"file\": %w", err))
+		return
+	}
+	if header.Size > maxSize {
+		fail(util.CallUserError, fmt.Errorf("file is %d bytes, the limit is %d", header.Size, maxSize))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		fail(util.CallServerError, err)
+		return
+	}
+	defer file.Close()
+
+	// Trust the content, not the client's file name or Content-Type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		fail(util.CallServerError, err)
+		return
+	}
+	head = head[:n]
+	contentType := strings.TrimSpace(strings.Split(http.DetectContentType(head), ";")[0])
+	if !uploadAllowed(contentType) {
+		fail(util.CallUserError, fmt.Errorf("files of type %s are not accepted", contentType))
+		return
+	}
+
+	object, err := files.Save(dir, uploadFileName(contentType), io.MultiReader(bytes.NewReader(head), file))
+	if err != nil {
+		fail(util.CallServerError, err)
+		return
+	}
+
+	if flash.Wanted(c) {
+		flash.SetFlash(c, table.Key, flash.Success)
+	}
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s file uploaded", table.Alias),
+		Data: map[string]interface{}{"file": object, "content_type": contentType, "original_name": header.Filename},
+	})
+}
+
+// uploadFileName generates a unique name with the extension of the sniffed type; client names are never used on disk
+func uploadFileName(contentType string) string {
+	random := make([]byte, 8)
+	rand.Read(random)
+	return time.Now().Format("20060102150405") + "_" + hex.EncodeToString(random) + uploadTypes[contentType]
+}
+
+// uploadContentType returns the MIME type of a stored file from the extension it was given on upload
+func uploadContentType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	for contentType, typeExt := range uploadTypes {
+		if typeExt == ext {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+func downloadOmnitagsFile(c *gin.Context, table *config.Table, files storage.Storage) {
+	dir, err := omnitagsRecordDir(c, table)
+	if err != nil {
+		return
+	}
+
+	reader, object, err := files.Open(dir, c.Param("name"))
+	if err != nil {
+		callFileError(c, "Failed to read file", err)
+		return
+	}
+	defer reader.Close()
+
+	// Set explicitly, the CORS middleware already put application/json in the header
+	c.Header("Content-Type", uploadContentType(object.Name))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, object.Size, uploadContentType(object.Name), reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("inline; filename=%q", object.Name),
+	})
+}
+
+func deleteOmnitagsFile(c *gin.Context, table *config.Table, files storage.Storage) {
+	dir, err := omnitagsRecordDir(c, table)
+	if err != nil {
+		return
+	}
+
+	if err := files.Delete(dir, c.Param("name")); err != nil {
+		callFileError(c, "Failed to delete file", err)
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  fmt.Sprintf("%s file deleted", table.Alias),
+		Data: nil,
+	})
+}
+
+// callFileError answers 404 for missing files, 400 for unsafe names and 500 otherwise
+func callFileError(c *gin.Context, msg string, err error) {
+	if errors.Is(err, storage.ErrInvalidName) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid file name",
+			Err: err,
+		})
+		return
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		util.CallErrorNotFound(c, util.APIErrorParams{
+			Msg: "File not found",
+			Err: fmt.Errorf("file %q not found", c.Param("name")),
+		})
+		return
+	}
+	util.CallServerError(c, util.APIErrorParams{
+		Msg: msg,
+		Err: err,
+	})
+}
@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khenjyjohnelson/golang-omnitags/storage"
+)
+
+// pngHeader is sniffed as image/png
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+func TestOmnitagsFileRoutes(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "uploads")
+	r := newOmnitagsTestRouterWithFiles(t, storage.NewLocal(root))
+	if res := serveOmnitags(t, r, http.MethodPost, "/omnitags/transaksi", "tamu", `{"nama": "a", "metode": "cash", "harga": 1}`); res.Code != http.StatusOK {
+		t.Fatalf("POST = %d, want 200", res.Code)
+	}
+
+	upload := func(filename, content string) omnitagsResponse {
+		t.Helper()
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+		form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/omnitags/transaksi/1/files", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Test-Role", "tamu")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		res := omnitagsResponse{Code: w.Code}
+		if err := json.Unmarshal(w.Body.Bytes(), &res.Body); err != nil {
+			t.Fatalf("upload %s: %v\n%s", filename, err, w.Body.String())
+		}
+		return res
+	}
+
+	// The client file name never reaches the disk
+	res := upload("../../../evil.png", pngHeader+"image")
+	if res.Code != http.StatusOK {
+		t.Fatalf("upload = %d, want 200", res.Code)
+	}
+	file, _ := res.Body.Data["file"].(map[string]interface{})
+	name, _ := file["name"].(string)
+	if filepath.Ext(name) != ".png" || name == "evil.png" {
+		t.Fatalf("stored name = %q, want a generated .png name", name)
+	}
+	if _, err := os.Stat(filepath.Join(root, "assets", "img", "tabel_f3", "1", name)); err != nil {
+		t.Errorf("the upload is not in the record directory: %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(parent, "*.png")); len(matches) > 0 {
+		t.Errorf("files were written outside the root: %v", matches)
+	}
+
+	if res := upload("script.png", "<html><script>alert(1)</script>"); res.Code != http.StatusBadRequest {
+		t.Errorf("upload of HTML named .png = %d, want 400", res.Code)
+	}
+
+	if res := serveOmnitags(t, r, http.MethodGet, "/omnitags/transaksi/1/files", "tamu", ""); res.Code != http.StatusOK || res.Body.Data["total"] != float64(1) {
+		t.Errorf("list = %d %v, want the one upload", res.Code, res.Body.Data)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/omnitags/transaksi/1/files/"+name, nil)
+	req.Header.Set("X-Test-Role", "tamu")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != pngHeader+"image" || w.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("download = %d %q %q, want the image", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	for _, tt := range []struct {
+		method string
+		name   string
+		code   int
+	}{
+		{http.MethodGet, "%2e%2e", http.StatusBadRequest},
+		{http.MethodGet, ".htaccess", http.StatusBadRequest},
+		{http.MethodDelete, "%2e%2e", http.StatusBadRequest},
+		{http.MethodGet, "missing.png", http.StatusNotFound},
+	} {
+		if res := serveOmnitags(t, r, tt.method, "/omnitags/transaksi/1/files/"+tt.name, "tamu", ""); res.Code != tt.code {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.name, res.Code, tt.code)
+		}
+	}
+
+	if res := serveOmnitags(t, r, http.MethodDelete, "/omnitags/transaksi/1/files/"+name, "tamu", ""); res.Code != http.StatusOK {
+		t.Errorf("delete = %d, want 200", res.Code)
+	}
+	if res := serveOmnitags(t, r, http.MethodGet, "/omnitags/transaksi/2/files", "tamu", ""); res.Code != http.StatusNotFound {
+		t.Errorf("list of a missing record = %d, want 404", res.Code)
+	}
+}
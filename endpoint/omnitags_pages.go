@@ -0,0 +1,101 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+	"github.com/khenjyjohnelson/golang-omnitags/view"
+)
+
+// omnitagsPage is the data a table view is rendered with
+type omnitagsPage struct {
+	Key   string
+	Table *config.Table
+	Rows  []map[string]interface{}
+	Total int64
+	// Filters lists the filter parameters of the table, for building search forms
+	Filters []config.FilterParam
+	Query   url.Values
+	// Flashes are the messages set by the previous request, e.g. after saving a form
+	Flashes []flash.Message
+}
+
+// RegisterOmnitagsPageRoutes mounts the HTML pages of every table, rendered from the Views paths
+// contents/<key>/index, contents/<key>/daftar and contents/<key>/admin. Requires the ViewRenderer middleware.
+func RegisterOmnitagsPageRoutes(rg *gin.RouterGroup, store *config.OmnitagsStore, access OmnitagsAccess) {
+	rg.GET("/:table", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { renderOmnitagsPage(c, table, "") }))
+	rg.GET("/:table/daftar", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { renderOmnitagsPage(c, table, "_daftar") }))
+	rg.GET("/:table/admin", withOmnitagsTable(store, access, func(c *gin.Context, table *config.Table) { renderOmnitagsPage(c, table, "_admin") }))
+}
+
+func renderOmnitagsPage(c *gin.Context, table *config.Table, suffix string) {
+	renderer := view.FromContext(c)
+	if renderer == nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "HTML views are not enabled",
+			Err: fmt.Errorf("no view renderer installed"),
+		})
+		return
+	}
+
+	omnitags := c.MustGet(omnitagsSnapshotKey).(*config.Omnitags)
+	path := omnitags.Views[table.Key+suffix]
+	if path == "" {
+		util.CallErrorNotFound(c, util.APIErrorParams{
+			Msg: "View not found",
+			Err: fmt.Errorf("%s has no view %q", table.Key, table.Key+suffix),
+		})
+		return
+	}
+
+	tmpl, err := renderer.Template(path, omnitags)
+	if err != nil {
+		if errors.Is(err, view.ErrNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "View not found",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to load %s", path),
+			Err: err,
+		})
+		return
+	}
+
+	limit, offset, keyword, groupByDate := parseQueryParams(c)
+	query := c.Request.URL.Query()
+	filters, errs := omnitags.BuildFilter(table, query)
+	if len(errs) > 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg:  fmt.Sprintf("Invalid filter for %s", table.Alias),
+			Err:  errs,
+			Data: map[string]interface{}{"errors": errs},
+		})
+		return
+	}
+
+	rows, total, err := fetchOmnitagsRows(table, filters, limit, offset, keyword, groupByDate)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to retrieve %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	page := omnitagsPage{Key: table.Key, Table: table, Rows: rows, Total: total, Filters: omnitags.FilterParams(table), Query: query, Flashes: flash.ConsumeFlashes(c)}
+	if err := view.HTML(c, http.StatusOK, tmpl, page); err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to render %s", path),
+			Err: err,
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package endpoint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/report"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+	"github.com/khenjyjohnelson/golang-omnitags/view"
+)
+
+// reportViews names the view of the HTML report formats, appended to the table key
+var reportViews = map[string]string{"html": "_laporan", "print": "_print"}
+
+// reportFilterOps is how applied filters are described in a report heading
+var reportFilterOps = map[config.FilterOp]string{
+	config.FilterMin:   ">=",
+	config.FilterMax:   "<=",
+	config.FilterEqual: "=",
+	config.FilterLike:  "contains",
+}
+
+// reportOmnitagsRows renders the filtered rows of a table as an HTML report, a print page, PDF or CSV.
+// It accepts the same query parameters as the list route; limit defaults to every row.
+func reportOmnitagsRows(c *gin.Context, table *config.Table) {
+	format := c.DefaultQuery("format", "html")
+	writer, err := report.NewWriter(format)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Unknown report format",
+			Err: err,
+		})
+		return
+	}
+
+	limit, offset, keyword, groupByDate := parseQueryParams(c)
+	omnitags := c.MustGet(omnitagsSnapshotKey).(*config.Omnitags)
+	query := c.Request.URL.Query()
+	filters, errs := omnitags.BuildFilter(table, query)
+	if len(errs) > 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg:  fmt.Sprintf("Invalid filter for %s", table.Alias),
+			Err:  errs,
+			Data: map[string]interface{}{"errors": errs},
+		})
+		return
+	}
+
+	// Views of the HTML formats may be overridden by a template file
+	html, isHTML := writer.(report.HTML)
+	if renderer := view.FromContext(c); isHTML && renderer != nil && omnitags.Views[table.Key+reportViews[format]] != "" {
+		html.Template, err = renderer.Template(omnitags.Views[table.Key+reportViews[format]], omnitags)
+		if err != nil && !errors.Is(err, view.ErrNotFound) {
+			util.CallServerError(c, util.APIErrorParams{
+				Msg: fmt.Sprintf("Failed to load the %s report view", table.Alias),
+				Err: err,
+			})
+			return
+		}
+		writer = html
+	}
+
+	rows, _, err := fetchOmnitagsRows(table, filters, limit, offset, keyword, groupByDate)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to retrieve %s", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	title := omnitags.Titles[table.Key+"_v4"]
+	if title == "" {
+		title = table.Alias + " Report"
+	}
+	r := &report.Report{Title: title, Generated: time.Now()}
+	if keyword != "" {
+		r.Filters = append(r.Filters, fmt.Sprintf("keyword %q", keyword))
+	}
+	for _, p := range omnitags.FilterParams(table) {
+		if value := strings.TrimSpace(query.Get(p.Param)); value != "" {
+			r.Filters = append(r.Filters, fmt.Sprintf("%s %s %s", reportHeader(p.Field), reportFilterOps[p.Op], value))
+		}
+	}
+	var fields []*config.Field
+	for _, f := range table.Fields {
+		if f.Name != "" && !f.Sensitive() {
+			fields = append(fields, f)
+			r.Columns = append(r.Columns, reportHeader(f))
+		}
+	}
+	for _, row := range rows {
+		cells := make([]string, len(fields))
+		for i, f := range fields {
+			cells[i] = reportCell(f, row[f.Name])
+		}
+		r.Rows = append(r.Rows, cells)
+	}
+
+	var buf bytes.Buffer
+	if err := writer.Write(&buf, r); err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Failed to render the %s report", table.Alias),
+			Err: err,
+		})
+		return
+	}
+
+	// Set explicitly, the CORS middleware already put application/json in the header
+	c.Header("Content-Type", writer.ContentType())
+	disposition := "attachment"
+	if isHTML {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, report.FileName(table.Name, writer, r.Generated)))
+	c.Data(http.StatusOK, writer.ContentType(), buf.Bytes())
+}
+
+// reportHeader is the column header of a field, its alias when it has one
+func reportHeader(f *config.Field) string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// reportCell formats a column value for a report; enum values show their alias when declared
+func reportCell(f *config.Field, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		value = string(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	}
+
+	text := fmt.Sprint(value)
+	for _, ev := range f.Values {
+		if ev.Value == text && ev.Alias != "" {
+			return ev.Alias
+		}
+	}
+	return text
+}
@@ -0,0 +1,259 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/middleware"
+	"github.com/khenjyjohnelson/golang-omnitags/storage"
+	"gorm.io/gorm"
+)
+
+// omnitagsTestEnvironment describes an allowed table and a restricted one with a sensitive field
+const omnitagsTestEnvironment = `{
+	"name": "test",
+	"values": [
+		{"key": "tabel_f3", "value": "transaksi", "enabled": true},
+		{"key": "tabel_f3_alias", "value": "Transaksi", "enabled": true},
+		{"key": "tabel_f3_field1", "value": "id", "enabled": true},
+		{"key": "tabel_f3_field2", "value": "nama", "enabled": true},
+		{"key": "tabel_f3_field3", "value": "metode", "enabled": true},
+		{"key": "tabel_f3_field3_value1", "value": "cash", "enabled": true},
+		{"key": "tabel_f3_field3_value2", "value": "transfer", "enabled": true},
+		{"key": "tabel_f3_field4", "value": "harga", "enabled": true},
+		{"key": "tabel_c2", "value": "users", "enabled": true},
+		{"key": "tabel_c2_alias", "value": "Users", "enabled": true},
+		{"key": "tabel_c2_field1", "value": "id", "enabled": true},
+		{"key": "tabel_c2_field2", "value": "email", "enabled": true},
+		{"key": "tabel_c2_field3", "value": "password", "enabled": true},
+		{"key": "tabel_c3", "value": "api_tokens", "enabled": true},
+		{"key": "tabel_c3_field1", "value": "token", "enabled": true}
+	]
+}`
+
+// newOmnitagsTestRouter serves the generic routes on an in-memory SQLite database.
+// Requests carry the session role given in their X-Test-Role header.
+func newOmnitagsTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	return newOmnitagsTestRouterWithFiles(t, storage.NewLocal(t.TempDir()))
+}
+
+// newOmnitagsTestRouterWithFiles also serves the file routes of the records from files
+func newOmnitagsTestRouterWithFiles(t *testing.T, files storage.Storage) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a new database
+	sqlDB.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"CREATE TABLE transaksi (id INTEGER PRIMARY KEY AUTOINCREMENT, nama TEXT, metode TEXT, harga INTEGER)",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT, password TEXT)",
+		"INSERT INTO users (email, password) VALUES ('admin@example.com', 'hash')",
+		"CREATE TABLE api_tokens (token TEXT PRIMARY KEY)",
+		"INSERT INTO api_tokens (token) VALUES ('abc')",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	connect := connectOmnitagsDB
+	connectOmnitagsDB = func() (*gorm.DB, error) { return db, nil }
+	t.Cleanup(func() { connectOmnitagsDB = connect })
+
+	path := filepath.Join(t.TempDir(), "test.postman_environment.json")
+	if err := os.WriteFile(path, []byte(omnitagsTestEnvironment), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := config.NewOmnitagsStore(config.OmnitagsSource{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	group := r.Group("/omnitags", func(c *gin.Context) {
+		c.Set(middleware.RoleContextKey, c.GetHeader("X-Test-Role"))
+	})
+	access := OmnitagsAccess{Tables: []string{"f3"}, AdminRoles: []string{"admin"}}
+	RegisterOmnitagsRoutes(group, store, access)
+	RegisterOmnitagsFileRoutes(group, store, access, files)
+	return r
+}
+
+// omnitagsResponse is the decoded APIResponse of a test request
+type omnitagsResponse struct {
+	Code    int
+	Cookies []*http.Cookie
+	Body    struct {
+		Success bool                   `json:"success"`
+		Data    map[string]interface{} `json:"data"`
+	}
+}
+
+func serveOmnitags(t *testing.T, r *gin.Engine, method, target, role, body string) omnitagsResponse {
+	t.Helper()
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Test-Role", role)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	res := omnitagsResponse{Code: w.Code, Cookies: w.Result().Cookies()}
+	if err := json.Unmarshal(w.Body.Bytes(), &res.Body); err != nil {
+		t.Fatalf("%s %s: %v\n%s", method, target, err, w.Body.String())
+	}
+	return res
+}
+
+func TestOmnitagsRoutes(t *testing.T) {
+	r := newOmnitagsTestRouter(t)
+	for i, body := range []string{
+		`{"nama": "diskon 50%", "metode": "cash", "harga": 10}`,
+		`{"nama": "diskon 500", "metode": "transfer", "harga": 20}`,
+		`{"nama": "kode_a", "metode": "cash", "harga": 30}`,
+	} {
+		res := serveOmnitags(t, r, http.MethodPost, "/omnitags/transaksi", "tamu", body)
+		if res.Code != http.StatusOK {
+			t.Fatalf("POST %s = %d, want 200", body, res.Code)
+		}
+		if res.Body.Data["id"] != float64(i+1) || res.Body.Data["metode"] == nil {
+			t.Errorf("POST %s returned %v, want the created row with id %d", body, res.Body.Data, i+1)
+		}
+		if len(res.Cookies) > 0 {
+			t.Errorf("POST from a JSON client sets cookies %v", res.Cookies)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		role   string
+		body   string
+		code   int
+		check  func(t *testing.T, data map[string]interface{})
+	}{
+		{
+			name: "list counts every row", method: http.MethodGet, target: "/omnitags/transaksi?limit=1", role: "tamu", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["total"] != float64(3) || len(data["transaksi"].([]interface{})) != 1 {
+					t.Errorf("data = %v, want total 3 and one row", data)
+				}
+			},
+		},
+		{
+			name: "filters apply to the total", method: http.MethodGet, target: "/omnitags/transaksi?min_harga=15&limit=1", role: "tamu", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["total"] != float64(2) {
+					t.Errorf("total = %v, want 2", data["total"])
+				}
+			},
+		},
+		{
+			name: "percent sign is literal", method: http.MethodGet, target: "/omnitags/transaksi?txt_nama=%25", role: "tamu", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["total"] != float64(1) {
+					t.Errorf("total = %v, want 1", data["total"])
+				}
+			},
+		},
+		{
+			name: "underscore in keyword is literal", method: http.MethodGet, target: "/omnitags/transaksi?keyword=_", role: "tamu", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["total"] != float64(1) {
+					t.Errorf("total = %v, want 1", data["total"])
+				}
+			},
+		},
+		{name: "invalid filter", method: http.MethodGet, target: "/omnitags/transaksi?txt_metode=credit", role: "tamu", code: http.StatusBadRequest},
+		{
+			name: "get", method: http.MethodGet, target: "/omnitags/transaksi/1", role: "tamu", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["nama"] != "diskon 50%" {
+					t.Errorf("data = %v, want the first row", data)
+				}
+			},
+		},
+		{
+			name: "update", method: http.MethodPatch, target: "/omnitags/transaksi/1", role: "tamu", body: `{"harga": 15}`, code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["harga"] != float64(15) || data["nama"] != "diskon 50%" {
+					t.Errorf("data = %v, want the updated row", data)
+				}
+			},
+		},
+		{name: "enum value not allowed", method: http.MethodPost, target: "/omnitags/transaksi", role: "tamu", body: `{"metode": "credit"}`, code: http.StatusBadRequest},
+		{name: "unknown field", method: http.MethodPost, target: "/omnitags/transaksi", role: "tamu", body: `{"diskon": 1}`, code: http.StatusBadRequest},
+		{name: "empty payload", method: http.MethodPost, target: "/omnitags/transaksi", role: "tamu", body: `{}`, code: http.StatusBadRequest},
+		{name: "empty update", method: http.MethodPatch, target: "/omnitags/transaksi/1", role: "tamu", body: `{}`, code: http.StatusBadRequest},
+		{name: "delete", method: http.MethodDelete, target: "/omnitags/transaksi/2", role: "tamu", code: http.StatusOK},
+		{name: "deleted row is gone", method: http.MethodGet, target: "/omnitags/transaksi/2", role: "tamu", code: http.StatusNotFound},
+		{name: "update a missing row", method: http.MethodPatch, target: "/omnitags/transaksi/9", role: "tamu", body: `{"harga": 1}`, code: http.StatusNotFound},
+		{name: "unknown table", method: http.MethodGet, target: "/omnitags/nothing", role: "admin", code: http.StatusNotFound},
+		{name: "restricted table", method: http.MethodGet, target: "/omnitags/users", role: "tamu", code: http.StatusForbidden},
+		{name: "restricted row", method: http.MethodGet, target: "/omnitags/users/1", role: "tamu", code: http.StatusForbidden},
+		{
+			name: "admin lists a restricted table without secrets", method: http.MethodGet, target: "/omnitags/users", role: "admin", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				for _, row := range data["users"].([]interface{}) {
+					if _, exists := row.(map[string]interface{})["password"]; exists {
+						t.Errorf("row %v has the password", row)
+					}
+				}
+			},
+		},
+		{
+			name: "admin gets a row without secrets", method: http.MethodGet, target: "/omnitags/users/1", role: "admin", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if _, exists := data["password"]; exists || data["email"] != "admin@example.com" {
+					t.Errorf("data = %v, want the email without the password", data)
+				}
+			},
+		},
+		{
+			name: "searching secrets matches nothing", method: http.MethodGet, target: "/omnitags/users?keyword=hash", role: "admin", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["total"] != float64(0) {
+					t.Errorf("total = %v, want 0", data["total"])
+				}
+			},
+		},
+		{
+			name: "keyword on a table without searchable fields", method: http.MethodGet, target: "/omnitags/api_tokens?keyword=abc", role: "admin", code: http.StatusOK,
+			check: func(t *testing.T, data map[string]interface{}) {
+				if data["total"] != float64(1) {
+					t.Errorf("total = %v, want 1", data["total"])
+				}
+			},
+		},
+		{name: "writing secrets", method: http.MethodPatch, target: "/omnitags/users/1", role: "admin", body: `{"password": "x"}`, code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := serveOmnitags(t, r, tt.method, tt.target, tt.role, tt.body)
+			if res.Code != tt.code {
+				t.Fatalf("%s %s = %d, want %d", tt.method, tt.target, res.Code, tt.code)
+			}
+			if tt.check != nil {
+				tt.check(t, res.Body.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,485 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
+	"github.com/khenjyjohnelson/golang-omnitags/middleware"
+	"github.com/khenjyjohnelson/golang-omnitags/model"
+	"github.com/khenjyjohnelson/golang-omnitags/report"
+	"github.com/khenjyjohnelson/golang-omnitags/storage"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+	"gorm.io/gorm"
+)
+
+// openAPIOperation documents a hand-written route; request and response are sample values of the bound and returned types
+type openAPIOperation struct {
+	Tag     string
+	Summary string
+	Public  bool
+	// Cookie operations are browser pages authenticated by the session cookie alone
+	Cookie   bool
+	Query    []string
+	Filters  []config.FilterParam
+	Request  interface{}
+	Response interface{}
+	// Bare responses are sent as they are, without the util.APIResponse envelope
+	Bare bool
+}
+
+// patientList and therapistList describe the data of the list endpoints
+type patientList struct {
+	Total    int64           `json:"total"`
+	Patients []model.Patient `json:"patients"`
+}
+
+type therapistList struct {
+	Total     int64             `json:"total"`
+	Therapist []model.Therapist `json:"therapist"`
+}
+
+// welcomeMessage is the body of GET /
+type welcomeMessage struct {
+	Message string `json:"message"`
+}
+
+// flashList describes the data of GET /flashes
+type flashList struct {
+	Total   int             `json:"total"`
+	Flashes []flash.Message `json:"flashes"`
+}
+
+// listQueryParams are the query parameters read by parseQueryParams
+var listQueryParams = []string{"limit", "offset", "keyword", "group_by_date"}
+
+// openAPIOperations documents the hand-written routes by "METHOD path"
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /": {Tag: "service", Summary: "Welcome message", Public: true, Response: welcomeMessage{}, Bare: true},
+
+	"POST /login":           {Tag: "auth", Summary: "Log in and receive a session token", Public: true, Request: LoginRequest{}, Response: ""},
+	"POST /signup":          {Tag: "auth", Summary: "Create an account and receive a session token", Public: true, Request: SignupRequest{}, Response: ""},
+	"DELETE /logout":        {Tag: "auth", Summary: "End the current session"},
+	"GET /flashes":          {Tag: "flash", Summary: "Read and clear the flash messages of earlier requests", Cookie: true, Response: flashList{}},
+	"GET /token/validate":   {Tag: "token", Summary: "Validate a session token and return its role", Public: true, Response: sessionRole{}},
+	"GET /openapi.json":     {Tag: "service", Summary: "This OpenAPI document", Public: true},
+	"GET /patient":          {Tag: "patients", Summary: "List patients", Query: listQueryParams, Response: patientList{}},
+	"POST /patient":         {Tag: "patients", Summary: "Register a patient", Public: true, Request: createPatientRequest{}},
+	"GET /patient/:id":      {Tag: "patients", Summary: "Get a patient", Response: model.Patient{}},
+	"PATCH /patient/:id":    {Tag: "patients", Summary: "Update a patient", Request: model.Patient{}, Response: model.Patient{}},
+	"DELETE /patient/:id":   {Tag: "patients", Summary: "Delete a patient"},
+	"GET /disease":          {Tag: "diseases", Summary: "List diseases", Response: []model.Disease{}},
+	"POST /disease":         {Tag: "diseases", Summary: "Create a disease", Request: createDiseaseRequest{}, Response: model.Disease{}},
+	"GET /disease/:id":      {Tag: "diseases", Summary: "Get a disease", Response: model.Disease{}},
+	"PATCH /disease/:id":    {Tag: "diseases", Summary: "Update a disease", Request: createDiseaseRequest{}, Response: model.Disease{}},
+	"DELETE /disease/:id":   {Tag: "diseases", Summary: "Delete a disease"},
+	"GET /therapist":        {Tag: "therapists", Summary: "List therapists", Query: listQueryParams, Response: therapistList{}},
+	"POST /therapist":       {Tag: "therapists", Summary: "Register a therapist", Request: createTherapistRequest{}},
+	"GET /therapist/:id":    {Tag: "therapists", Summary: "Get a therapist", Response: model.Therapist{}},
+	"PATCH /therapist/:id":  {Tag: "therapists", Summary: "Update a therapist, approval cannot be changed here", Request: model.Therapist{}},
+	"PUT /therapist/:id":    {Tag: "therapists", Summary: "Approve a therapist, is_approved must be true", Request: model.Therapist{}},
+	"DELETE /therapist/:id": {Tag: "therapists", Summary: "Delete a therapist"},
+
+	"GET /admin/omnitags":               {Tag: "admin", Summary: "Summary of the resolved Omnitags configuration"},
+	"GET /admin/omnitags/groups":        {Tag: "admin", Summary: "List Omnitags table groups in display order with their tables"},
+	"GET /admin/omnitags/relations":     {Tag: "admin", Summary: "List table relations, or draw them as a mermaid or dot ER diagram", Query: []string{"format", "group"}},
+	"GET /admin/omnitags/tables":        {Tag: "admin", Summary: "List Omnitags tables", Query: []string{"prefix"}},
+	"GET /admin/omnitags/tables/:table": {Tag: "admin", Summary: "Get an Omnitags table with its fields and derived values"},
+	"GET /admin/omnitags/keys":          {Tag: "admin", Summary: "List environment keys, secrets redacted", Query: []string{"prefix"}},
+	"GET /admin/omnitags/derived":       {Tag: "admin", Summary: "List derived values with the key they came from", Query: []string{"prefix", "map"}},
+}
+
+// OpenAPIHandler serves an OpenAPI 3 document describing the routes registered on r.
+// Omnitags tables are read from the store on every request, so the document follows hot reloads.
+func OpenAPIHandler(r *gin.Engine, store *config.OmnitagsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, buildOpenAPI(r.Routes(), store.Get()))
+	}
+}
+
+// openAPIBuilder collects paths and component schemas
+type openAPIBuilder struct {
+	paths   map[string]map[string]interface{}
+	schemas map[string]interface{}
+}
+
+func buildOpenAPI(routes gin.RoutesInfo, omnitags *config.Omnitags) map[string]interface{} {
+	b := &openAPIBuilder{paths: make(map[string]map[string]interface{}), schemas: make(map[string]interface{})}
+	b.schemaOf(reflect.TypeOf(util.APIResponse{}))
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Path+routes[i].Method < routes[j].Path+routes[j].Method })
+	for _, route := range routes {
+		if strings.HasPrefix(route.Path, "/omnitags/:table") || strings.HasPrefix(route.Path, "/pages/:table") {
+			b.addOmnitagsRoute(route.Method, route.Path, omnitags)
+			continue
+		}
+		op, documented := openAPIOperations[route.Method+" "+route.Path]
+		if !documented {
+			op = openAPIOperation{Tag: "other", Summary: route.Handler}
+		}
+		b.addOperation(route.Method, route.Path, op, nil, nil)
+	}
+
+	appName := config.LoadConfig().AppName
+	if appName == "" {
+		appName = "golang-omnitags"
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": appName, "version": "1.0.0"},
+		"paths":   b.paths,
+		"components": map[string]interface{}{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]interface{}{
+				"apiToken":      map[string]interface{}{"type": "http", "scheme": "bearer", "description": "APITOKEN, required on every route by the CORS middleware"},
+				"sessionToken":  map[string]interface{}{"type": "apiKey", "in": "header", "name": "session-token"},
+				"sessionCookie": map[string]interface{}{"type": "apiKey", "in": "cookie", "name": middleware.SessionCookieName, "description": "Set by POST /login, for browser pages"},
+			},
+		},
+	}
+}
+
+// addOperation adds one operation; request and response schemas override the sample values of op when set
+func (b *openAPIBuilder) addOperation(method, path string, op openAPIOperation, request, response map[string]interface{}) {
+	var params []interface{}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, map[string]interface{}{"name": name, "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}})
+		}
+	}
+	for _, name := range op.Query {
+		param := map[string]interface{}{"name": name, "in": "query", "schema": map[string]interface{}{"type": "string"}}
+		switch name {
+		case "limit", "offset":
+			param["schema"] = map[string]interface{}{"type": "integer"}
+		case "format":
+			param["schema"] = map[string]interface{}{"type": "string", "enum": report.Formats(), "default": "html"}
+		case "group_by_date":
+			param["schema"] = map[string]interface{}{"type": "string", "enum": []string{"last_2_days", "last_3_months", "last_6_months"}}
+		}
+		params = append(params, param)
+	}
+	for _, filter := range op.Filters {
+		// Time filters also accept plain dates, so the date-time format is dropped
+		param := omnitagsFieldSchema(filter.Field)
+		delete(param, "nullable")
+		delete(param, "format")
+		delete(param, "description")
+		description := fmt.Sprintf("%s (%s)", filter.Field.Alias, filter.Op)
+		if filter.Op == config.FilterLike {
+			description = "Substring of " + filter.Field.Alias
+		}
+		params = append(params, map[string]interface{}{"name": filter.Param, "in": "query", "description": description, "schema": param})
+	}
+
+	if request == nil && op.Request != nil {
+		request = b.schemaOf(reflect.TypeOf(op.Request))
+	}
+	if response == nil && op.Response != nil {
+		response = b.schemaOf(reflect.TypeOf(op.Response))
+	}
+	envelope := map[string]interface{}{"$ref": "#/components/schemas/APIResponse"}
+	if response != nil {
+		envelope = map[string]interface{}{"allOf": []interface{}{
+			envelope,
+			map[string]interface{}{"type": "object", "properties": map[string]interface{}{"data": response}},
+		}}
+	}
+	errorResponse := map[string]interface{}{"content": map[string]interface{}{"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/APIResponse"}}}}
+
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{"description": "Success", "content": map[string]interface{}{"application/json": map[string]interface{}{"schema": envelope}}},
+		"400": mergeMap(errorResponse, "description", "Invalid request"),
+		"500": mergeMap(errorResponse, "description", "Server error"),
+	}
+	if op.Bare {
+		responses = map[string]interface{}{
+			"200": map[string]interface{}{"description": "Success", "content": map[string]interface{}{"application/json": map[string]interface{}{"schema": response}}},
+		}
+	}
+	operation := map[string]interface{}{
+		"tags":      []string{op.Tag},
+		"summary":   op.Summary,
+		"responses": responses,
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+	if request != nil {
+		operation["requestBody"] = map[string]interface{}{"required": true, "content": map[string]interface{}{"application/json": map[string]interface{}{"schema": request}}}
+	}
+	security := map[string]interface{}{"apiToken": []string{}}
+	if op.Cookie {
+		security = map[string]interface{}{"sessionCookie": []string{}}
+		operation["responses"].(map[string]interface{})["401"] = mergeMap(errorResponse, "description", "Missing or expired session cookie")
+	} else if !op.Public {
+		security["sessionToken"] = []string{}
+		operation["responses"].(map[string]interface{})["401"] = mergeMap(errorResponse, "description", "Missing or expired session token")
+	}
+	operation["security"] = []interface{}{security}
+
+	openAPIPath := strings.Join(segments, "/")
+	if b.paths[openAPIPath] == nil {
+		b.paths[openAPIPath] = make(map[string]interface{})
+	}
+	b.paths[openAPIPath][strings.ToLower(method)] = operation
+}
+
+func mergeMap(m map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := map[string]interface{}{key: value}
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// addOmnitagsRoute expands a generic /omnitags/:table route into one path per table
+func (b *openAPIBuilder) addOmnitagsRoute(method, path string, omnitags *config.Omnitags) {
+	if strings.Contains(path, "/:id/files") {
+		b.addOmnitagsFileRoute(method, path, omnitags)
+		return
+	}
+	if strings.HasPrefix(path, "/pages/") {
+		b.addOmnitagsPageRoute(method, path, omnitags)
+		return
+	}
+	for _, table := range omnitags.Schema.Tables {
+		if table.Name == "" || table.PrimaryKey() == nil {
+			continue
+		}
+		name := "omnitags_" + table.Name
+		if b.schemas[name] == nil {
+			b.schemas[name] = omnitagsTableSchema(table)
+		}
+		row := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+
+		op := openAPIOperation{Tag: "omnitags"}
+		var request, response map[string]interface{}
+		switch {
+		case strings.HasSuffix(path, "/report"):
+			op.Summary, op.Query, op.Filters = table.Alias+" report as HTML, a print page, PDF or CSV", append([]string{"format"}, listQueryParams...), omnitags.FilterParams(table)
+		case method == http.MethodGet && !strings.HasSuffix(path, "/:id"):
+			op.Summary, op.Query, op.Filters = "List "+table.Alias, listQueryParams, omnitags.FilterParams(table)
+			response = map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+				"total":    map[string]interface{}{"type": "integer"},
+				table.Name: map[string]interface{}{"type": "array", "items": row},
+			}}
+		case method == http.MethodPost:
+			op.Summary, request, response = "Create "+table.Alias, row, row
+		case method == http.MethodGet:
+			op.Summary, response = "Get "+table.Alias, row
+		case method == http.MethodPatch:
+			op.Summary, request, response = "Update "+table.Alias, row, row
+		case method == http.MethodDelete:
+			op.Summary = "Delete " + table.Alias
+		default:
+			op.Summary = method + " " + table.Alias
+		}
+		openAPIPath := strings.Replace(path, ":table", table.Name, 1)
+		b.addOperation(method, openAPIPath, op, request, response)
+
+		// Reports are documents, not the JSON envelope
+		if strings.HasSuffix(path, "/report") {
+			content := make(map[string]interface{})
+			for _, format := range report.Formats() {
+				writer, _ := report.NewWriter(format)
+				contentType := strings.Split(writer.ContentType(), ";")[0]
+				content[contentType] = map[string]interface{}{"schema": map[string]interface{}{"type": "string", "format": "binary"}}
+			}
+			operation := b.paths[openAPIPath][strings.ToLower(method)].(map[string]interface{})
+			operation["responses"].(map[string]interface{})["200"] = map[string]interface{}{"description": "The rendered report", "content": content}
+		}
+	}
+}
+
+// addOmnitagsPageRoute expands an HTML page route into one path per table
+func (b *openAPIBuilder) addOmnitagsPageRoute(method, path string, omnitags *config.Omnitags) {
+	suffix := ""
+	if !strings.HasSuffix(path, "/:table") {
+		suffix = "_" + path[strings.LastIndex(path, "/")+1:]
+	}
+	for _, table := range omnitags.Schema.Tables {
+		view := omnitags.Views[table.Key+suffix]
+		if table.Name == "" || table.PrimaryKey() == nil || view == "" {
+			continue
+		}
+
+		op := openAPIOperation{Tag: "pages", Cookie: true, Summary: fmt.Sprintf("%s page rendered from %s", table.Alias, view), Query: listQueryParams, Filters: omnitags.FilterParams(table)}
+		openAPIPath := strings.Replace(path, ":table", table.Name, 1)
+		b.addOperation(method, openAPIPath, op, nil, nil)
+		operation := b.paths[openAPIPath][strings.ToLower(method)].(map[string]interface{})
+		operation["responses"].(map[string]interface{})["200"] = map[string]interface{}{"description": "The rendered page", "content": map[string]interface{}{"text/html": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}}}
+	}
+}
+
+// addOmnitagsFileRoute expands a file route into one path per table with an upload path
+func (b *openAPIBuilder) addOmnitagsFileRoute(method, path string, omnitags *config.Omnitags) {
+	file := b.schemaOf(reflect.TypeOf(storage.Object{}))
+	for _, table := range omnitags.Schema.Tables {
+		if table.Name == "" || table.PrimaryKey() == nil || omnitags.VUploadPath[table.Key] == "" {
+			continue
+		}
+
+		op := openAPIOperation{Tag: "omnitags files"}
+		var response map[string]interface{}
+		switch {
+		case method == http.MethodGet && strings.HasSuffix(path, "/files"):
+			op.Summary = "List files of " + table.Alias
+			response = map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+				"total": map[string]interface{}{"type": "integer"},
+				"files": map[string]interface{}{"type": "array", "items": file},
+			}}
+		case method == http.MethodPost:
+			op.Summary = "Upload a file to " + table.Alias
+			response = map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+				"file":          file,
+				"content_type":  map[string]interface{}{"type": "string"},
+				"original_name": map[string]interface{}{"type": "string"},
+			}}
+		case method == http.MethodGet:
+			op.Summary = "Download a file of " + table.Alias
+		case method == http.MethodDelete:
+			op.Summary = "Delete a file of " + table.Alias
+		default:
+			op.Summary = method + " " + table.Alias + " files"
+		}
+		openAPIPath := strings.Replace(path, ":table", table.Name, 1)
+		b.addOperation(method, openAPIPath, op, nil, response)
+
+		// Uploads are multipart and downloads are the raw file, not the JSON envelope
+		operation := b.paths[strings.NewReplacer(":id", "{id}", ":name", "{name}").Replace(openAPIPath)][strings.ToLower(method)].(map[string]interface{})
+		switch {
+		case method == http.MethodPost:
+			operation["requestBody"] = map[string]interface{}{"required": true, "content": map[string]interface{}{"multipart/form-data": map[string]interface{}{"schema": map[string]interface{}{
+				"type":       "object",
+				"required":   []string{"file"},
+				"properties": map[string]interface{}{"file": map[string]interface{}{"type": "string", "format": "binary"}},
+			}}}}
+		case method == http.MethodGet && strings.HasSuffix(path, "/:name"):
+			operation["responses"].(map[string]interface{})["200"] = map[string]interface{}{"description": "The stored file", "content": map[string]interface{}{"application/octet-stream": map[string]interface{}{"schema": map[string]interface{}{"type": "string", "format": "binary"}}}}
+		}
+	}
+}
+
+// omnitagsTableSchema describes a row of an Omnitags table from its field kinds and aliases, without sensitive fields
+func omnitagsTableSchema(table *config.Table) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, f := range table.Fields {
+		if f.Name != "" && !f.Sensitive() {
+			properties[f.Name] = omnitagsFieldSchema(f)
+		}
+	}
+	return map[string]interface{}{"type": "object", "title": table.Alias, "description": table.Key, "properties": properties, "additionalProperties": false}
+}
+
+// omnitagsFieldSchema describes a column of an Omnitags table
+func omnitagsFieldSchema(f *config.Field) map[string]interface{} {
+	property := map[string]interface{}{"type": "string", "nullable": true}
+	switch f.Kind() {
+	case config.KindID, config.KindInt:
+		property["type"] = "integer"
+	case config.KindTime:
+		property["format"] = "date-time"
+	case config.KindEnum:
+		if values := f.EnumValues(); len(values) > 0 {
+			property["enum"] = values
+		}
+	}
+	if f.Alias != "" {
+		property["description"] = f.Alias
+	}
+	return property
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// schemaOf derives a JSON schema from a Go type the way encoding/json would marshal it.
+// Named structs are added to the components and referenced.
+func (b *openAPIBuilder) schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case deletedAtType:
+		return map[string]interface{}{"type": "string", "format": "date-time", "nullable": true}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, exists := b.schemas[name]; !exists {
+			b.schemas[name] = nil // placeholder against recursive types
+			b.schemas[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema lists the JSON properties of a struct, flattening embedded structs without a json tag
+func (b *openAPIBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				collect(field.Type)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = b.schemaOf(field.Type)
+			if strings.Contains(field.Tag.Get("binding"), "required") {
+				required = append(required, name)
+			}
+		}
+	}
+	collect(t)
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+func TestOpenAPIWelcomeIsBare(t *testing.T) {
+	doc := buildOpenAPI(gin.RoutesInfo{{Method: "GET", Path: "/"}, {Method: "GET", Path: "/flashes"}}, config.NewConfig())
+	paths := doc["paths"].(map[string]map[string]interface{})
+
+	schemaOf := func(path string) map[string]interface{} {
+		responses := paths[path]["get"].(map[string]interface{})["responses"].(map[string]interface{})
+		content := responses["200"].(map[string]interface{})["content"].(map[string]interface{})
+		return content["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	}
+
+	if ref := schemaOf("/")["$ref"]; ref != "#/components/schemas/WelcomeMessage" {
+		t.Errorf("GET / schema = %v, want the bare WelcomeMessage", schemaOf("/"))
+	}
+	if _, enveloped := schemaOf("/flashes")["allOf"]; !enveloped {
+		t.Errorf("GET /flashes schema = %v, want the APIResponse envelope", schemaOf("/flashes"))
+	}
+	// Browsers read their flashes with the session cookie, without the API headers
+	security := paths["/flashes"]["get"].(map[string]interface{})["security"].([]interface{})
+	if scheme := security[0].(map[string]interface{}); len(scheme) != 1 || scheme["sessionCookie"] == nil {
+		t.Errorf("GET /flashes security = %v, want only sessionCookie", security)
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["WelcomeMessage"].(map[string]interface{})["properties"].(map[string]interface{})
+	if _, exists := properties["message"]; !exists || len(properties) != 1 {
+		t.Errorf("WelcomeMessage properties = %v, want only message", properties)
+	}
+}
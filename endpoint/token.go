@@ -10,6 +10,12 @@ import (
 	"github.com/khenjyjohnelson/golang-omnitags/util"
 )
 
+// sessionRole is a session together with the name of its user's role
+type sessionRole struct {
+	model.Session
+	Role string `json:"role"`
+}
+
 func ValidateToken(c *gin.Context) {
 	sessionToken := c.GetHeader("session-token")
 	if sessionToken == "" {
@@ -27,10 +33,7 @@ func ValidateToken(c *gin.Context) {
 	}
 
 	// Join sessions, users, and roles to retrieve the role name aliased as 'role'
-	var result struct {
-		model.Session
-		Role string `json:"role"`
-	}
+	var result sessionRole
 	err = db.Table("sessions").
 		Select("sessions.*, roles.name as role").
 		Joins("JOIN users ON sessions.user_id = users.id").
@@ -0,0 +1,93 @@
+package flash
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCookieSize keeps the cookie below the 4096 bytes browsers accept, oldest messages are dropped first
+const maxCookieSize = 3800
+
+// CookieStore keeps pending messages in a signed cookie, so no server-side session is needed
+type CookieStore struct {
+	Name   string
+	Path   string
+	Secure bool
+	secret []byte
+}
+
+// NewCookieStore signs cookies with secret; a random secret is used when it is empty,
+// which drops pending messages when the service restarts and differs between instances
+func NewCookieStore(secret string) (*CookieStore, error) {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate a flash cookie secret: %w", err)
+		}
+	}
+	return &CookieStore{Name: "flash", Path: "/", secret: key}, nil
+}
+
+func (s *CookieStore) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Load returns the messages of the cookie; missing, tampered or malformed cookies hold none
+func (s *CookieStore) Load(c *gin.Context) []Message {
+	value, err := c.Cookie(s.Name)
+	if err != nil {
+		return nil
+	}
+	payload, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+	var messages []Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil
+	}
+	return messages
+}
+
+// Save replaces the cookie with messages, or deletes it when there are none
+func (s *CookieStore) Save(c *gin.Context, messages []Message) {
+	// Only the last state of the request is sent
+	header := c.Writer.Header()
+	cookies := header.Values("Set-Cookie")
+	header.Del("Set-Cookie")
+	for _, cookie := range cookies {
+		if !strings.HasPrefix(cookie, s.Name+"=") {
+			header.Add("Set-Cookie", cookie)
+		}
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	for len(messages) > 0 {
+		data, err := json.Marshal(messages)
+		if err != nil {
+			break
+		}
+		payload := base64.RawURLEncoding.EncodeToString(data)
+		if value := payload + "." + s.sign(payload); len(value) <= maxCookieSize {
+			c.SetCookie(s.Name, value, 0, s.Path, "", s.Secure, true)
+			return
+		}
+		messages = messages[1:]
+	}
+	c.SetCookie(s.Name, "", -1, s.Path, "", s.Secure, true)
+}
@@ -0,0 +1,141 @@
+package flash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// saveCookie returns the flash cookie store writes for messages
+func saveCookie(t *testing.T, store *CookieStore, messages []Message) *http.Cookie {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	store.Save(c, messages)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == store.Name {
+			return cookie
+		}
+	}
+	t.Fatalf("Save did not set the %s cookie", store.Name)
+	return nil
+}
+
+// loadCookie reads the messages of a request carrying cookie
+func loadCookie(store *CookieStore, cookie *http.Cookie) []Message {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.AddCookie(cookie)
+	return store.Load(c)
+}
+
+func newTestCookieStore(t *testing.T, secret string) *CookieStore {
+	t.Helper()
+	store, err := NewCookieStore(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestCookieStoreRoundTrip(t *testing.T) {
+	store := newTestCookieStore(t, "secret")
+	messages := []Message{
+		{Key: "tabel_c2", Kind: Success, Name: "pesan_users", Text: "users successfully saved!", Script: `$(".users").modal("show")`},
+		{Key: "tabel_f3", Kind: Error, Name: "pesan_transaksi", Text: "transaksi tidak bisa diupload!"},
+	}
+
+	cookie := saveCookie(t, store, messages)
+	if !cookie.HttpOnly {
+		t.Error("flash cookie is not HTTP-only")
+	}
+	got := loadCookie(store, cookie)
+	if len(got) != len(messages) {
+		t.Fatalf("Load = %v, want %v", got, messages)
+	}
+	for i := range messages {
+		if got[i] != messages[i] {
+			t.Errorf("message %d = %+v, want %+v", i, got[i], messages[i])
+		}
+	}
+}
+
+func TestCookieStoreRejectsTampering(t *testing.T) {
+	store := newTestCookieStore(t, "secret")
+	cookie := saveCookie(t, store, []Message{{Key: "tabel_c2", Kind: Success, Text: "users successfully saved!"}})
+	payload, signature, _ := strings.Cut(cookie.Value, ".")
+	forged := saveCookie(t, newTestCookieStore(t, "other"), []Message{{Key: "tabel_c2", Kind: Success, Text: "forged"}})
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"payload changed", strings.ToUpper(payload[:4]) + payload[4:] + "." + signature},
+		{"signature changed", payload + "." + strings.Repeat("A", len(signature))},
+		{"signature missing", payload},
+		{"signed with another secret", forged.Value},
+		{"not base64", "!!!." + signature},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loadCookie(store, &http.Cookie{Name: store.Name, Value: tt.value}); got != nil {
+				t.Errorf("Load = %v, want no messages", got)
+			}
+		})
+	}
+}
+
+func TestCookieStoreRandomSecret(t *testing.T) {
+	first := newTestCookieStore(t, "")
+	second := newTestCookieStore(t, "")
+	cookie := saveCookie(t, first, []Message{{Key: "tabel_c2", Kind: Success, Text: "users successfully saved!"}})
+	if got := loadCookie(first, cookie); len(got) != 1 {
+		t.Fatalf("Load with the same store = %v, want 1 message", got)
+	}
+	if got := loadCookie(second, cookie); got != nil {
+		t.Errorf("Load with another random secret = %v, want no messages", got)
+	}
+}
+
+func TestWanted(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		optIn       string
+		want        bool
+	}{
+		{name: "json api client", accept: "application/json", contentType: "application/json"},
+		{name: "no headers"},
+		{name: "any type", accept: "*/*", contentType: "application/json"},
+		{name: "browser", accept: "text/html,application/xhtml+xml,*/*;q=0.8", contentType: "application/x-www-form-urlencoded", want: true},
+		{name: "form post", contentType: "application/x-www-form-urlencoded", want: true},
+		{name: "multipart upload", contentType: "multipart/form-data; boundary=x", want: true},
+		{name: "multipart upload expecting json", accept: "application/json", contentType: "multipart/form-data; boundary=x"},
+		{name: "opt in", accept: "application/json", optIn: "1", want: true},
+		{name: "opt out", accept: "text/html", optIn: "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			for header, value := range map[string]string{"Accept": tt.accept, "Content-Type": tt.contentType, HeaderName: tt.optIn} {
+				if value != "" {
+					c.Request.Header.Set(header, value)
+				}
+			}
+			if got := Wanted(c); got != tt.want {
+				t.Errorf("Wanted = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
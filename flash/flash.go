@@ -0,0 +1,137 @@
+// Package flash carries one-time messages from one request to the next, e.g. from a form post to the page it redirects to.
+package flash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+// Kind picks the Omnitags map a message text comes from
+type Kind string
+
+const (
+	// Success reads Flash1Msg, e.g. "users successfully saved!"
+	Success Kind = "success"
+	// Error reads FlashMsg, e.g. "users tidak bisa diupload!"
+	Error Kind = "error"
+)
+
+// Message is a flash resolved from Omnitags for one key
+type Message struct {
+	Key  string `json:"key"`
+	Kind Kind   `json:"kind"`
+	// Name is the Flash variable the views read, e.g. pesan_users
+	Name string `json:"name"`
+	Text string `json:"text"`
+	// Script is the FlashFunc snippet that shows the message, e.g. $(".users").modal("show")
+	Script string `json:"script,omitempty"`
+}
+
+// Store keeps the pending messages of a client between requests
+type Store interface {
+	Load(c *gin.Context) []Message
+	Save(c *gin.Context, messages []Message)
+}
+
+// ContextKey stores the flash state of a request in the gin context
+const ContextKey = "flash"
+
+// state is the flash state of one request
+type state struct {
+	store    Store
+	omnitags *config.OmnitagsStore
+	pending  []Message
+	added    []Message
+	consumed bool
+}
+
+// Start loads the pending messages of the request from store; SetFlash resolves texts against omnitags
+func Start(c *gin.Context, store Store, omnitags *config.OmnitagsStore) {
+	c.Set(ContextKey, &state{store: store, omnitags: omnitags, pending: store.Load(c)})
+}
+
+func stateOf(c *gin.Context) (*state, error) {
+	if s, ok := c.Get(ContextKey); ok {
+		return s.(*state), nil
+	}
+	return nil, fmt.Errorf("flash messages are not enabled")
+}
+
+// save writes the unread messages and the ones added by this request back to the store
+func (s *state) save(c *gin.Context) {
+	var messages []Message
+	if !s.consumed {
+		messages = append(messages, s.pending...)
+	}
+	s.store.Save(c, append(messages, s.added...))
+}
+
+// New resolves the message of kind for key, e.g. tabel_c2
+func New(omnitags *config.Omnitags, key string, kind Kind) (Message, error) {
+	var text string
+	switch kind {
+	case Success:
+		text = omnitags.Flash1Msg[key]
+	case Error:
+		text = omnitags.FlashMsg[key]
+	default:
+		return Message{}, fmt.Errorf("unknown flash kind %q", kind)
+	}
+	if text == "" {
+		return Message{}, fmt.Errorf("no %s flash message for %q", kind, key)
+	}
+	return Message{Key: key, Kind: kind, Name: omnitags.Flash[key], Text: text, Script: omnitags.FlashFunc[key]}, nil
+}
+
+// HeaderName lets API clients opt in to flash messages, e.g. X-Flash: 1
+const HeaderName = "X-Flash"
+
+// Wanted reports whether the request comes from a browser page or form, which shows the messages
+// on the page it is redirected to, or opts in with the X-Flash header. JSON API clients get no flash cookie.
+func Wanted(c *gin.Context) bool {
+	if optIn, err := strconv.ParseBool(c.GetHeader(HeaderName)); err == nil {
+		return optIn
+	}
+	accept := c.GetHeader("Accept")
+	if strings.Contains(accept, gin.MIMEHTML) {
+		return true
+	}
+	switch c.ContentType() {
+	case gin.MIMEPOSTForm, gin.MIMEMultipartPOSTForm:
+		return !strings.Contains(accept, gin.MIMEJSON)
+	}
+	return false
+}
+
+// SetFlash queues the message of kind for key; it is shown by the next request that consumes flashes
+func SetFlash(c *gin.Context, key string, kind Kind) error {
+	s, err := stateOf(c)
+	if err != nil {
+		return err
+	}
+	message, err := New(s.omnitags.Get(), key, kind)
+	if err != nil {
+		return err
+	}
+	s.added = append(s.added, message)
+	s.save(c)
+	return nil
+}
+
+// ConsumeFlashes returns the messages set by earlier requests and removes them from the store
+func ConsumeFlashes(c *gin.Context) []Message {
+	s, err := stateOf(c)
+	if err != nil {
+		return []Message{}
+	}
+	if s.consumed {
+		return []Message{}
+	}
+	s.consumed = true
+	s.save(c)
+	return append([]Message{}, s.pending...)
+}
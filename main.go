@@ -2,106 +2,166 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-
-	// "log"
-	// "net/http"
-	// "time"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	_ "time/tzdata"
 
-	// "github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin"
 	"github.com/khenjyjohnelson/golang-omnitags/config"
-	// "github.com/khenjyjohnelson/golang-omnitags/endpoint"
-	// "github.com/khenjyjohnelson/golang-omnitags/middleware"
-	// "github.com/khenjyjohnelson/golang-omnitags/model"
-	// "gorm.io/gorm"
-	// "gorm.io/gorm/logger"
+	"github.com/khenjyjohnelson/golang-omnitags/endpoint"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
+	"github.com/khenjyjohnelson/golang-omnitags/middleware"
+	"github.com/khenjyjohnelson/golang-omnitags/model"
+	"github.com/khenjyjohnelson/golang-omnitags/storage"
+	"github.com/khenjyjohnelson/golang-omnitags/view"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func main() {
-	// Initialize Config and load JSON data
-	config := config.ReadConfig()
-
-	// Example Outputs
-	fmt.Println("Database Name:", config.Aliases["database"])
-	fmt.Println("Table A1 Name:", config.Titles["tabel_a1_alias_v2"])
-	fmt.Println("Upload Path for A1:", config.VUploadPath["tabel_a1"])
-	fmt.Println("Flash Message for A1:", config.Views["tabel_a1_daftar"])
+	// Load the configuration first, it reads .env in local mode and the Omnitags defaults below come from the environment
+	cfg := config.LoadConfig()
+
+	// Pick the Omnitags environment, flags take precedence over OMNITAGSFILE and OMNITAGSENV
+	omnitagsSource := config.OmnitagsSourceFromEnv()
+	flag.StringVar(&omnitagsSource.Path, "omnitags-file", omnitagsSource.Path, "Omnitags environment file or directory")
+	flag.StringVar(&omnitagsSource.Environment, "omnitags-env", omnitagsSource.Environment, "Omnitags environment name, e.g. dev, staging or prod")
+	flag.StringVar(&omnitagsSource.NamingFile, "omnitags-naming", omnitagsSource.NamingFile, "JSON file overriding the Omnitags naming conventions")
+	omnitagsReload, _ := time.ParseDuration(os.Getenv("OMNITAGSRELOAD"))
+	flag.DurationVar(&omnitagsReload, "omnitags-reload", omnitagsReload, "poll interval for hot reloading the Omnitags environment, 0 disables it")
+	// Unknown Omnitags keys panic in strict mode, on by default for local and development
+	omnitagsStrict, err := strconv.ParseBool(os.Getenv("OMNITAGSSTRICT"))
+	if err != nil {
+		omnitagsStrict = cfg.AppEnv == "local" || cfg.AppEnv == "development"
+	}
+	flag.BoolVar(&omnitagsStrict, "omnitags-strict", omnitagsStrict, "panic on unknown Omnitags keys instead of falling back to defaults")
+	flag.Parse()
+	config.SetOmnitagsSource(omnitagsSource)
+	config.SetStrict(omnitagsStrict)
+
+	// Initialize Omnitags and load JSON data
+	omnitags, err := config.OmnitagsConfigStore()
+	if err != nil {
+		log.Fatalf("Error loading Omnitags configuration: %v", err)
+	}
+	if omnitagsReload > 0 {
+		go omnitags.Watch(context.Background(), omnitagsReload)
+	}
+
+	// Set the timezone to Asia/Jakarta
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		log.Fatalf("Error loading timezone: %v", err)
+	}
+	time.Local = location
+	gormConfig := &gorm.Config{}
+	if cfg.AppEnv == "production" {
+		gormConfig.Logger = logger.Default.LogMode(logger.Silent)
+	} else {
+		gormConfig.Logger = logger.Default.LogMode(logger.Info)
+	}
+	db, err := config.ConnectMySQL()
+	if err != nil {
+		log.Fatalf("Error connecting to MySQL: %v", err)
+	}
+	db.AutoMigrate(&model.Patient{}, &model.Disease{}, &model.User{}, &model.Session{}, &model.Therapist{}, &model.Role{})
+
+	// Set Gin mode from config
+	gin.SetMode(cfg.GinMode)
+
+	// Create a Gin router with default middleware
+	r := gin.Default()
+
+	// Use custom CORS middleware, browser pages are authenticated by the session cookie instead of the API token
+	r.Use(middleware.CORSMiddleware("/pages", "/flashes"))
+
+	// HTML views are loaded from VIEWROOT and re-read on every request outside release mode
+	renderer := view.NewRenderer(os.Getenv("VIEWROOT"))
+	renderer.Reload = cfg.GinMode != gin.ReleaseMode
+	r.Use(middleware.ViewRenderer(renderer))
+
+	// Flash messages survive one redirect in a cookie signed with FLASHSECRET
+	flashSecret := os.Getenv("FLASHSECRET")
+	if flashSecret == "" {
+		if cfg.GinMode == gin.ReleaseMode {
+			log.Fatalf("FLASHSECRET is required in release mode")
+		}
+		log.Printf("FLASHSECRET is not set, flash messages are signed with a random key and lost on restart")
+	}
+	flashStore, err := flash.NewCookieStore(flashSecret)
+	if err != nil {
+		log.Fatalf("Error creating the flash cookie store: %v", err)
+	}
+	r.Use(middleware.Flashes(flashStore, omnitags))
+
+	// Basic HTTP handler for root path
+	r.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Welcome to %s!", cfg.AppName),
+		})
+	})
+	// Group routes that require a valid login token
+	auth := r.Group("/")
+	auth.Use(middleware.ValidateLoginToken())
+	{
+		auth.GET("/patient", endpoint.ListPatients)
+		auth.GET("/patient/:id", endpoint.GetPatientInfo)
+		auth.PATCH("/patient/:id", endpoint.UpdatePatient)
+		auth.DELETE("/patient/:id", endpoint.DeletePatient)
+
+		auth.DELETE("/logout", endpoint.Logout)
+
+		auth.GET("/disease", endpoint.ListDiseases)
+		auth.POST("/disease", endpoint.CreateDisease)
+		auth.GET("/disease/:id", endpoint.GetDiseaseInfo)
+		auth.PATCH("/disease/:id", endpoint.UpdateDisease)
+		auth.DELETE("/disease/:id", endpoint.DeleteDisease)
+
+		auth.GET("/therapist", endpoint.ListTherapist)
+		auth.POST("/therapist", endpoint.CreateTherapist)
+		auth.GET("/therapist/:id", endpoint.GetTherapistInfo)
+		auth.PATCH("/therapist/:id", endpoint.UpdateTherapist)
+		auth.DELETE("/therapist/:id", endpoint.DeleteTherapist)
+		auth.PUT("/therapist/:id", endpoint.TherapistApproval)
+
+		// Generic CRUD routes for the tables described by Omnitags, OMNITAGSTABLES lists the ones open to every user
+		omnitagsAccess := endpoint.OmnitagsAccessFromEnv()
+		endpoint.RegisterOmnitagsRoutes(auth.Group("/omnitags"), omnitags, omnitagsAccess)
+
+		// Files attached to Omnitags records, stored under their VUploadPath directories
+		endpoint.RegisterOmnitagsFileRoutes(auth.Group("/omnitags"), omnitags, omnitagsAccess, storage.NewLocal(os.Getenv("UPLOADROOT")))
+
+		// Read-only inspection of the resolved Omnitags configuration, for the roles of OMNITAGSADMINROLES only
+		endpoint.RegisterOmnitagsAdminRoutes(auth.Group("/admin/omnitags", middleware.RequireRole(middleware.AdminRoles()...)), omnitags)
+	}
+
+	// Routes for browsers signed in with the session cookie of Login
+	browser := r.Group("/", middleware.ValidateSessionCookie())
+	{
+		browser.GET("/flashes", endpoint.ListFlashes)
+
+		// HTML pages of the Omnitags tables, rendered from their Views
+		endpoint.RegisterOmnitagsPageRoutes(browser.Group("/pages"), omnitags, endpoint.OmnitagsAccessFromEnv())
+	}
+
+	// the exception for create patient so it can be accessed without login
+	r.POST("/patient", endpoint.CreatePatient)
+
+	r.POST("/login", endpoint.Login)
+	r.POST("/signup", endpoint.Signup)
+	r.GET("/token/validate", endpoint.ValidateToken)
+	r.GET("/openapi.json", endpoint.OpenAPIHandler(r, omnitags))
+
+	// Start server on specified port
+	address := fmt.Sprintf(":%d", cfg.AppPort)
+	if err := r.Run(address); err != nil {
+		log.Fatalf("error starting server: %v", err)
+	}
 }
-
-// func main() {
-// 	// Load the configuration
-// 	cfg := config.LoadConfig()
-
-// 	// Set the timezone to Asia/Jakarta
-// 	location, err := time.LoadLocation("Asia/Jakarta")
-// 	if err != nil {
-// 		log.Fatalf("Error loading timezone: %v", err)
-// 	}
-// 	time.Local = location
-// 	gormConfig := &gorm.Config{}
-// 	if cfg.AppEnv == "production" {
-// 		gormConfig.Logger = logger.Default.LogMode(logger.Silent)
-// 	} else {
-// 		gormConfig.Logger = logger.Default.LogMode(logger.Info)
-// 	}
-// 	db, err := config.ConnectMySQL()
-// 	if err != nil {
-// 		log.Fatalf("Error connecting to MySQL: %v", err)
-// 	}
-// 	db.AutoMigrate(&model.Patient{}, &model.Disease{}, &model.User{}, &model.Session{}, &model.Therapist{}, &model.Role{})
-
-// 	// Set Gin mode from config
-// 	gin.SetMode(cfg.GinMode)
-
-// 	// Create a Gin router with default middleware
-// 	r := gin.Default()
-
-// 	// Use custom CORS middleware
-// 	r.Use(middleware.CORSMiddleware())
-
-// 	// Basic HTTP handler for root path
-// 	r.GET("/", func(c *gin.Context) {
-// 		c.JSON(http.StatusOK, gin.H{
-// 			"message": fmt.Sprintf("Welcome to %s!", cfg.AppName),
-// 		})
-// 	})
-// 	// Group routes that require a valid login token
-// 	auth := r.Group("/")
-// 	auth.Use(middleware.ValidateLoginToken())
-// 	{
-// 		auth.GET("/patient", endpoint.ListPatients)
-// 		auth.GET("/patient/:id", endpoint.GetPatientInfo)
-// 		auth.PATCH("/patient/:id", endpoint.UpdatePatient)
-// 		auth.DELETE("/patient/:id", endpoint.DeletePatient)
-
-// 		auth.DELETE("/logout", endpoint.Logout)
-
-// 		auth.GET("/disease", endpoint.ListDiseases)
-// 		auth.POST("/disease", endpoint.CreateDisease)
-// 		auth.GET("/disease/:id", endpoint.GetDiseaseInfo)
-// 		auth.PATCH("/disease/:id", endpoint.UpdateDisease)
-// 		auth.DELETE("/disease/:id", endpoint.DeleteDisease)
-
-// 		auth.GET("/therapist", endpoint.ListTherapist)
-// 		auth.POST("/therapist", endpoint.CreateTherapist)
-// 		auth.GET("/therapist/:id", endpoint.GetTherapistInfo)
-// 		auth.PATCH("/therapist/:id", endpoint.UpdateTherapist)
-// 		auth.DELETE("/therapist/:id", endpoint.DeleteTherapist)
-// 		auth.PUT("/therapist/:id", endpoint.TherapistApproval)
-// 	}
-
-// 	// the exception for create patient so it can be accessed without login
-// 	r.POST("/patient", endpoint.CreatePatient)
-
-// 	r.POST("/login", endpoint.Login)
-// 	r.POST("/signup", endpoint.Signup)
-// 	r.GET("/token/validate", endpoint.ValidateToken)
-
-// 	// Start server on specified port
-// 	address := fmt.Sprintf(":%d", cfg.AppPort)
-// 	if err := r.Run(address); err != nil {
-// 		log.Fatalf("error starting server: %v", err)
-// 	}
-// }
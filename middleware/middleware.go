@@ -9,8 +9,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/flash"
 	"github.com/khenjyjohnelson/golang-omnitags/model"
 	"github.com/khenjyjohnelson/golang-omnitags/util"
+	"github.com/khenjyjohnelson/golang-omnitags/view"
 )
 
 func tokenValidator(c *gin.Context, expectedToken string) bool {
@@ -64,13 +66,14 @@ func setCorsHeaders(c *gin.Context) {
 }
 
 // CORSMiddleware configures CORS headers for incoming requests.
-func CORSMiddleware() gin.HandlerFunc {
+// Requests below one of browserPaths skip the API token check, browsers cannot send it; use ValidateSessionCookie there.
+func CORSMiddleware(browserPaths ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Set CORS headers
 		setCorsHeaders(c)
 
 		// Call tokenValidator after setting CORS headers.
-		if !tokenValidator(c, fmt.Sprintf("Bearer %s", os.Getenv("APITOKEN"))) {
+		if !isBrowserPath(c.Request.URL.Path, browserPaths) && !tokenValidator(c, fmt.Sprintf("Bearer %s", os.Getenv("APITOKEN"))) {
 			return
 		}
 
@@ -84,33 +87,157 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+// isBrowserPath reports whether path is one of prefixes or below it
+func isBrowserPath(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionCookieName is the cookie Login stores the session token in for browser pages
+const SessionCookieName = "session_token"
+
+// SessionTokenContextKey stores the validated session token of the request in the gin context
+const SessionTokenContextKey = "session-token"
+
 func ValidateLoginToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionToken := c.GetHeader("session-token")
-		if sessionToken == "" {
-			util.CallUserNotAuthorized(c, util.APIErrorParams{
-				Msg: "Session token not provided",
-				Err: fmt.Errorf("session token not provided"),
+		validateSession(c, c.GetHeader("session-token"))
+	}
+}
+
+// ValidateSessionCookie is ValidateLoginToken for browser pages, it reads the token from the SessionCookieName cookie.
+// Only mount GET routes behind it, the cookie is sent with cross-site top-level navigations.
+func ValidateSessionCookie() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionToken, _ := c.Cookie(SessionCookieName)
+		validateSession(c, sessionToken)
+	}
+}
+
+// validateSession aborts the request unless sessionToken belongs to an unexpired session
+func validateSession(c *gin.Context, sessionToken string) {
+	if sessionToken == "" {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Session token not provided",
+			Err: fmt.Errorf("session token not provided"),
+		})
+		c.Abort()
+		return
+	}
+
+	// Connect to the database
+	db, err := config.ConnectMySQL()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to connect to MySQL"})
+		c.Abort()
+		return
+	}
+
+	// Find the session record in the database based on sessionToken
+	var session model.Session
+	if err := db.Where("session_token = ? AND expires_at > ? AND deleted_at IS NULL", sessionToken, time.Now()).First(&session).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
+		c.Abort()
+		return
+	}
+	c.Set(SessionTokenContextKey, sessionToken)
+	c.Next()
+}
+
+// RoleContextKey caches the role name of the session in the gin context
+const RoleContextKey = "role"
+
+// SessionRole returns the name of the role of the user owning the session token of the request
+func SessionRole(c *gin.Context) (string, error) {
+	if role, ok := c.Get(RoleContextKey); ok {
+		return role.(string), nil
+	}
+
+	db, err := config.ConnectMySQL()
+	if err != nil {
+		return "", err
+	}
+	var role string
+	err = db.Table("sessions").
+		Select("roles.name").
+		Joins("JOIN users ON sessions.user_id = users.id").
+		Joins("JOIN roles ON users.role_id = roles.id").
+		Where("session_token = ? AND expires_at > ? AND sessions.deleted_at IS NULL", sessionToken(c), time.Now()).
+		Limit(1).
+		Scan(&role).Error
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", fmt.Errorf("session has no role")
+	}
+	c.Set(RoleContextKey, role)
+	return role, nil
+}
+
+// sessionToken returns the token validated for the request, or the session-token header
+func sessionToken(c *gin.Context) string {
+	if token := c.GetString(SessionTokenContextKey); token != "" {
+		return token
+	}
+	return c.GetHeader("session-token")
+}
+
+// HasRole reports whether the session of the request has one of roles
+func HasRole(c *gin.Context, roles []string) bool {
+	role, err := SessionRole(c)
+	return err == nil && util.Contains(role, roles)
+}
+
+// RequireRole rejects requests whose session does not have one of roles; use it after ValidateLoginToken
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasRole(c, roles) {
+			util.CallForbidden(c, util.APIErrorParams{
+				Msg: "Insufficient role",
+				Err: fmt.Errorf("one of the roles %s is required", strings.Join(roles, ", ")),
 			})
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Connect to the database
-		db, err := config.ConnectMySQL()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to connect to MySQL"})
-			c.Abort()
-			return
-		}
+// DefaultAdminRole is the role AdminRoles falls back to, the administrator value of the users role enum
+const DefaultAdminRole = "administrator"
 
-		// Find the session record in the database based on sessionToken
-		var session model.Session
-		if err := db.Where("session_token = ? AND expires_at > ? AND deleted_at IS NULL", sessionToken, time.Now()).First(&session).Error; err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
-			c.Abort()
-			return
+// AdminRoles returns the roles of OMNITAGSADMINROLES, a comma-separated list, or DefaultAdminRole when it is unset
+func AdminRoles() []string {
+	var roles []string
+	for _, role := range strings.Split(os.Getenv("OMNITAGSADMINROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
 		}
+	}
+	if len(roles) == 0 {
+		return []string{DefaultAdminRole}
+	}
+	return roles
+}
+
+// ViewRenderer makes renderer available to handlers through view.FromContext
+func ViewRenderer(renderer *view.Renderer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(view.ContextKey, renderer)
+		c.Next()
+	}
+}
+
+// Flashes loads the flash messages of each request from store, see flash.SetFlash and flash.ConsumeFlashes
+func Flashes(store flash.Store, omnitags *config.OmnitagsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		flash.Start(c, store, omnitags)
 		c.Next()
 	}
 }
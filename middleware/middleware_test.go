@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+	"github.com/khenjyjohnelson/golang-omnitags/util"
+)
+
+func TestCORSMiddlewareBrowserPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("APITOKEN", "secret")
+	r := gin.New()
+	r.Use(CORSMiddleware("/pages"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/pages/:table", ok)
+	r.GET("/pagesx", ok)
+	r.GET("/patient", ok)
+
+	tests := []struct {
+		target        string
+		authorization string
+		code          int
+	}{
+		{"/patient", "", http.StatusUnauthorized},
+		{"/patient", "Bearer secret", http.StatusOK},
+		{"/pages/users", "", http.StatusOK},
+		{"/pagesx", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("GET %s with %q = %d, want %d", tt.target, tt.authorization, w.Code, tt.code)
+		}
+	}
+}
+
+func TestValidateSessionCookieWithoutCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/pages/users", ValidateSessionCookie(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// The session-token header is not enough for browser pages
+	req := httptest.NewRequest(http.MethodGet, "/pages/users", nil)
+	req.Header.Set("session-token", "abc")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("GET without the session cookie = %d, want 401", w.Code)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// The session role is cached in the context, so no database is needed
+	r.Use(func(c *gin.Context) {
+		if role := c.GetHeader("X-Test-Role"); role != "" {
+			c.Set(RoleContextKey, role)
+		}
+	})
+	r.GET("/admin", RequireRole("administrator", "accounting"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		role string
+		code int
+	}{
+		{"administrator", http.StatusOK},
+		{"accounting", http.StatusOK},
+		{"tamu", http.StatusForbidden},
+		{"Administrator", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("X-Test-Role", tt.role)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("GET /admin as %q = %d, want %d", tt.role, w.Code, tt.code)
+		}
+	}
+}
+
+func TestAdminRoles(t *testing.T) {
+	tests := []struct {
+		env  string
+		want []string
+	}{
+		{"", []string{DefaultAdminRole}},
+		{" , ", []string{DefaultAdminRole}},
+		{"administrator, accounting", []string{"administrator", "accounting"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("OMNITAGSADMINROLES", tt.env)
+		if got := AdminRoles(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AdminRoles with %q = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultAdminRoleIsARole(t *testing.T) {
+	store, err := config.NewOmnitagsStore(config.OmnitagsSource{Path: "../app.postman_environment.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := store.Get().Schema.TableByName("users")
+	if users == nil || users.Field("role") == nil {
+		t.Fatal("the environment file has no users.role field")
+	}
+	if roles := users.Field("role").EnumValues(); !util.Contains(DefaultAdminRole, roles) {
+		t.Errorf("DefaultAdminRole %q is not one of the roles %v", DefaultAdminRole, roles)
+	}
+}
@@ -0,0 +1,5 @@
+// Package omnitags holds the GORM models generated from app.postman_environment.json.
+// Do not edit the group_*.go files by hand, regenerate them instead.
+package omnitags
+
+//go:generate go run ../../cmd/omnitags gen models -omnitags-file ../../app.postman_environment.json -out .
@@ -0,0 +1,24 @@
+// Code generated by omnitags gen models. DO NOT EDIT.
+
+package omnitags
+
+// OtWebsite maps the ot_website table (tabel_a1): Website Settings.
+type OtWebsite struct {
+	// Website ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Website Name
+	NamaWebsite string `gorm:"column:nama_website" json:"nama_website"`
+	// Address
+	Alamat string `gorm:"column:alamat;type:text" json:"alamat"`
+	// Email
+	Email string `gorm:"column:email" json:"email"`
+	// Phone Number
+	Phone string `gorm:"column:phone" json:"phone"`
+	// Theme ID
+	IDTheme uint `gorm:"column:id_theme" json:"id_theme"`
+}
+
+// TableName returns the table name of OtWebsite.
+func (OtWebsite) TableName() string {
+	return "ot_website"
+}
@@ -0,0 +1,289 @@
+// Code generated by omnitags gen models. DO NOT EDIT.
+
+package omnitags
+
+import "time"
+
+// OtDecoration maps the ot_decoration table (tabel_b1): Website Decoration.
+type OtDecoration struct {
+	// Decor ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Image Code
+	Kode string `gorm:"column:kode" json:"kode"`
+	// Key
+	Kunci string `gorm:"column:kunci" json:"kunci"`
+	// Photo
+	Img string `gorm:"column:img" json:"img"`
+	// Logo
+	Icons string `gorm:"column:icons" json:"icons"`
+	// Type
+	Tipe string `gorm:"column:tipe" json:"tipe"`
+	// Theme ID
+	IDTheme uint `gorm:"column:id_theme" json:"id_theme"`
+}
+
+// TableName returns the table name of OtDecoration.
+func (OtDecoration) TableName() string {
+	return "ot_decoration"
+}
+
+// OtEventsStatus holds the allowed values of ot_events.status.
+type OtEventsStatus string
+
+const (
+	// Active
+	OtEventsStatusAktif OtEventsStatus = "aktif"
+	// Inactive
+	OtEventsStatusNonaktif OtEventsStatus = "nonaktif"
+)
+
+// OtEvents maps the ot_events table (tabel_b2): Event Website.
+type OtEvents struct {
+	// Event ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Event Name
+	Nama string `gorm:"column:nama" json:"nama"`
+	// Slogan
+	Slogan string `gorm:"column:slogan" json:"slogan"`
+	// Photo
+	Img string `gorm:"column:img" json:"img"`
+	// Description
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+	// Status
+	Status OtEventsStatus `gorm:"column:status" json:"status"`
+	// Theme ID
+	IDTheme uint `gorm:"column:id_theme" json:"id_theme"`
+}
+
+// TableName returns the table name of OtEvents.
+func (OtEvents) TableName() string {
+	return "ot_events"
+}
+
+// Migrations maps the migrations table (tabel_b3): Migrations.
+type Migrations struct {
+	// Migration ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Migration
+	Migration string `gorm:"column:migration" json:"migration"`
+	// Batch
+	Batch int `gorm:"column:batch" json:"batch"`
+}
+
+// TableName returns the table name of Migrations.
+func (Migrations) TableName() string {
+	return "migrations"
+}
+
+// FailedJobs maps the failed_jobs table (tabel_b4): Failed Jobs.
+type FailedJobs struct {
+	// ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// UUID
+	UUID string `gorm:"column:uuid" json:"uuid"`
+	// Connection
+	Connection string `gorm:"column:connection" json:"connection"`
+	// Queue
+	Queue string `gorm:"column:queue" json:"queue"`
+	// Payload
+	Payload string `gorm:"column:payload;type:text" json:"payload"`
+	// Exception
+	Exception string `gorm:"column:exception;type:text" json:"exception"`
+	// Failed At
+	FailedAt *time.Time `gorm:"column:failed_at" json:"failed_at"`
+}
+
+// TableName returns the table name of FailedJobs.
+func (FailedJobs) TableName() string {
+	return "failed_jobs"
+}
+
+// OtLicensesStatus holds the allowed values of ot_licenses.status.
+type OtLicensesStatus string
+
+const (
+	// Active
+	OtLicensesStatusAktif OtLicensesStatus = "aktif"
+	// Inactive
+	OtLicensesStatusMati OtLicensesStatus = "mati"
+)
+
+// OtLicenses maps the ot_licenses table (tabel_b5): License.
+type OtLicenses struct {
+	// License ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// License Name
+	Nama string `gorm:"column:nama" json:"nama"`
+	// Content
+	Konten string `gorm:"column:konten;type:text" json:"konten"`
+	// Photo
+	Img string `gorm:"column:img" json:"img"`
+	// License Link
+	Link string `gorm:"column:link" json:"link"`
+	// License Status
+	Status OtLicensesStatus `gorm:"column:status" json:"status"`
+	// Theme ID
+	IDTheme uint `gorm:"column:id_theme" json:"id_theme"`
+}
+
+// TableName returns the table name of OtLicenses.
+func (OtLicenses) TableName() string {
+	return "ot_licenses"
+}
+
+// OtSosmedStatus holds the allowed values of ot_sosmed.status.
+type OtSosmedStatus string
+
+const (
+	// Active
+	OtSosmedStatusAktif OtSosmedStatus = "aktif"
+	// Inactive
+	OtSosmedStatusTidakAktif OtSosmedStatus = "tidak_aktif"
+)
+
+// OtSosmed maps the ot_sosmed table (tabel_b6): Social Media Account.
+type OtSosmed struct {
+	// Social Media ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Platform
+	Platform string `gorm:"column:platform" json:"platform"`
+	// Social Media Name
+	Nama string `gorm:"column:nama" json:"nama"`
+	// Link
+	Link string `gorm:"column:link" json:"link"`
+	// Fontawesome Icon
+	Icon string `gorm:"column:icon" json:"icon"`
+	// Status
+	Status OtSosmedStatus `gorm:"column:status" json:"status"`
+	// Theme ID
+	IDTheme uint `gorm:"column:id_theme" json:"id_theme"`
+}
+
+// TableName returns the table name of OtSosmed.
+func (OtSosmed) TableName() string {
+	return "ot_sosmed"
+}
+
+// OtThemes maps the ot_themes table (tabel_b7): Website Themes.
+type OtThemes struct {
+	// Theme ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Theme Name
+	Nama string `gorm:"column:nama" json:"nama"`
+	// Favicon
+	Favicon string `gorm:"column:favicon" json:"favicon"`
+	// Logo
+	Logo string `gorm:"column:logo" json:"logo"`
+	// Photo
+	Foto string `gorm:"column:foto" json:"foto"`
+	// Description
+	Deskripsi string `gorm:"column:deskripsi;type:text" json:"deskripsi"`
+}
+
+// TableName returns the table name of OtThemes.
+func (OtThemes) TableName() string {
+	return "ot_themes"
+}
+
+// OtNotifTypeNotifType holds the allowed values of ot_notif_type.notif_type.
+type OtNotifTypeNotifType string
+
+const (
+	OtNotifTypeNotifTypeNewNotificationAvailable    OtNotifTypeNotifType = "new_notification_available"
+	OtNotifTypeNotifTypeNewMessageReceived          OtNotifTypeNotifType = "new_message_received"
+	OtNotifTypeNotifTypeImportantMessageWarning     OtNotifTypeNotifType = "important_message_warning"
+	OtNotifTypeNotifTypeActionCompletedSuccessfully OtNotifTypeNotifType = "action_completed_successfully"
+	OtNotifTypeNotifTypeInformationalNote           OtNotifTypeNotifType = "informational_note"
+	OtNotifTypeNotifTypeActionFailedError           OtNotifTypeNotifType = "action_failed_error"
+	OtNotifTypeNotifTypeNeedAssistanceHelp          OtNotifTypeNotifType = "need_assistance_help"
+	OtNotifTypeNotifTypePositiveFeedbackReceived    OtNotifTypeNotifType = "positive_feedback_received"
+	OtNotifTypeNotifTypeNegativeFeedbackReceived    OtNotifTypeNotifType = "negative_feedback_received"
+	OtNotifTypeNotifTypeUpcomingEventReminder       OtNotifTypeNotifType = "upcoming_event_reminder"
+)
+
+// OtNotifType maps the ot_notif_type table (tabel_b8): Notification Types.
+type OtNotifType struct {
+	// Notif ID
+	IDNotifType uint `gorm:"column:id_notif_type;primaryKey" json:"id_notif_type"`
+	// Notif Type
+	NotifType OtNotifTypeNotifType `gorm:"column:notif_type" json:"notif_type"`
+	// Title
+	Title string `gorm:"column:title" json:"title"`
+	// Icon
+	Icon string `gorm:"column:icon" json:"icon"`
+}
+
+// TableName returns the table name of OtNotifType.
+func (OtNotifType) TableName() string {
+	return "ot_notif_type"
+}
+
+// OtNotifications maps the ot_notifications table (tabel_b9): Notifications.
+type OtNotifications struct {
+	// Notif ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// User ID
+	IDUser uint `gorm:"column:id_user" json:"id_user"`
+	// Notification Type
+	NotifType string `gorm:"column:notif_type" json:"notif_type"`
+	// Description
+	Description string `gorm:"column:description;type:text" json:"description"`
+	// Created At
+	CreatedAt *time.Time `gorm:"column:created_at" json:"created_at"`
+	// Read At
+	ReadAt *time.Time `gorm:"column:read_at" json:"read_at"`
+}
+
+// TableName returns the table name of OtNotifications.
+func (OtNotifications) TableName() string {
+	return "ot_notifications"
+}
+
+// Gallery maps the gallery table (tabel_b10): Gallery.
+type Gallery struct {
+	// Gallery ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Image
+	Img string `gorm:"column:img" json:"img"`
+	// Keterangan
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+	// ID Departemen
+	DepartmentID uint `gorm:"column:department_id" json:"department_id"`
+}
+
+// TableName returns the table name of Gallery.
+func (Gallery) TableName() string {
+	return "gallery"
+}
+
+// OtPages maps the ot_pages table (tabel_b11): Website Pages.
+type OtPages struct {
+	// Page Id
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Page URL
+	PageURL string `gorm:"column:page_url" json:"page_url"`
+	// Page Name
+	PageName string `gorm:"column:page_name" json:"page_name"`
+}
+
+// TableName returns the table name of OtPages.
+func (OtPages) TableName() string {
+	return "ot_pages"
+}
+
+// OtActivityLog maps the ot_activity_log table (tabel_b12): Activity Logs.
+type OtActivityLog struct {
+	// Activity ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// User ID
+	IDUser uint `gorm:"column:id_user" json:"id_user"`
+	// Notification Type
+	NotifType string `gorm:"column:notif_type" json:"notif_type"`
+	// Description
+	Description string `gorm:"column:description;type:text" json:"description"`
+}
+
+// TableName returns the table name of OtActivityLog.
+func (OtActivityLog) TableName() string {
+	return "ot_activity_log"
+}
@@ -0,0 +1,61 @@
+// Code generated by omnitags gen models. DO NOT EDIT.
+
+package omnitags
+
+// Students maps the students table (tabel_c1): Students.
+type Students struct {
+	// Student ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// First Name
+	FirstName string `gorm:"column:first_name" json:"first_name"`
+	// Last Name
+	LastName string `gorm:"column:last_name" json:"last_name"`
+	// Email
+	Email string `gorm:"column:email" json:"email"`
+	// Student Number
+	StudentNumber string `gorm:"column:student_number" json:"student_number"`
+	// Major ID
+	MajorID uint `gorm:"column:major_id" json:"major_id"`
+}
+
+// TableName returns the table name of Students.
+func (Students) TableName() string {
+	return "students"
+}
+
+// UsersRole holds the allowed values of users.role.
+type UsersRole string
+
+const (
+	// Accounting
+	UsersRoleAccounting UsersRole = "accounting"
+	// Administrator
+	UsersRoleAdministrator UsersRole = "administrator"
+	// Receptionist
+	UsersRoleResepsionis UsersRole = "resepsionis"
+	// Guest
+	UsersRoleTamu UsersRole = "tamu"
+)
+
+// Users maps the users table (tabel_c2): Users.
+type Users struct {
+	// User ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Name
+	Nama string `gorm:"column:nama" json:"nama"`
+	// Email
+	Email string `gorm:"column:email" json:"email"`
+	// Password
+	Password string `gorm:"column:password" json:"-"`
+	// Phone Number
+	Phone string `gorm:"column:phone" json:"phone"`
+	// Role
+	Role UsersRole `gorm:"column:role" json:"role"`
+	// Login Count
+	LoginCount int `gorm:"column:login_count" json:"login_count"`
+}
+
+// TableName returns the table name of Users.
+func (Users) TableName() string {
+	return "users"
+}
@@ -0,0 +1,55 @@
+// Code generated by omnitags gen models. DO NOT EDIT.
+
+package omnitags
+
+// PersonalAccessTokens maps the personal_access_tokens table (tabel_d1): Personal Access Token.
+type PersonalAccessTokens struct {
+}
+
+// TableName returns the table name of PersonalAccessTokens.
+func (PersonalAccessTokens) TableName() string {
+	return "personal_access_tokens"
+}
+
+// PasswordResets maps the password_resets table (tabel_d2): Password Resets.
+type PasswordResets struct {
+	// Email
+	Email string `gorm:"column:email;primaryKey" json:"email"`
+	// Token
+	Token string `gorm:"column:token" json:"-"`
+}
+
+// TableName returns the table name of PasswordResets.
+func (PasswordResets) TableName() string {
+	return "password_resets"
+}
+
+// LoginHistories maps the login_histories table (tabel_d3): Login Histories.
+type LoginHistories struct {
+	// ID Login History
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// User ID
+	IDUser uint `gorm:"column:id_user" json:"id_user"`
+	// Device Type
+	DeviceType string `gorm:"column:device_type" json:"device_type"`
+}
+
+// TableName returns the table name of LoginHistories.
+func (LoginHistories) TableName() string {
+	return "login_histories"
+}
+
+// OtClicks maps the ot_clicks table (tabel_d4): User Engagements.
+type OtClicks struct {
+	// Click Id
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// User Id
+	UserID uint `gorm:"column:user_id" json:"user_id"`
+	// Page Id
+	PageID uint `gorm:"column:page_id" json:"page_id"`
+}
+
+// TableName returns the table name of OtClicks.
+func (OtClicks) TableName() string {
+	return "ot_clicks"
+}
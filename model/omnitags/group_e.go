@@ -0,0 +1,163 @@
+// Code generated by omnitags gen models. DO NOT EDIT.
+
+package omnitags
+
+import "time"
+
+// Faculty maps the faculty table (tabel_e1): Faculty.
+type Faculty struct {
+	// Faculty ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// First Name
+	FirstName string `gorm:"column:first_name" json:"first_name"`
+	// Last Name
+	LastName string `gorm:"column:last_name" json:"last_name"`
+	// Email
+	Email string `gorm:"column:email" json:"email"`
+	// Department ID
+	DepartmentID uint `gorm:"column:department_id" json:"department_id"`
+}
+
+// TableName returns the table name of Faculty.
+func (Faculty) TableName() string {
+	return "faculty"
+}
+
+// Majors maps the majors table (tabel_e2): Majors.
+type Majors struct {
+	// Major ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Major Name
+	MajorName string `gorm:"column:major_name" json:"major_name"`
+	// Department ID
+	DepartmentID uint `gorm:"column:department_id" json:"department_id"`
+	// Keterangan
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+	// Keunggulan
+	Keunggulan string `gorm:"column:keunggulan" json:"keunggulan"`
+	// Prospek Karir
+	Prospek string `gorm:"column:prospek" json:"prospek"`
+}
+
+// TableName returns the table name of Majors.
+func (Majors) TableName() string {
+	return "majors"
+}
+
+// Courses maps the courses table (tabel_e3): Courses.
+type Courses struct {
+	// Course ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Course Name
+	CourseName string `gorm:"column:course_name" json:"course_name"`
+	// Course Code
+	CourseCode string `gorm:"column:course_code" json:"course_code"`
+	// Department ID
+	DepartmentID uint `gorm:"column:department_id" json:"department_id"`
+	// Professor ID
+	ProfessorID uint `gorm:"column:professor_id" json:"professor_id"`
+}
+
+// TableName returns the table name of Courses.
+func (Courses) TableName() string {
+	return "courses"
+}
+
+// Departments maps the departments table (tabel_e4): Departments.
+type Departments struct {
+	// Department ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Department Name
+	DepartmentName string `gorm:"column:department_name" json:"department_name"`
+	// Gambar
+	Img string `gorm:"column:img" json:"img"`
+}
+
+// TableName returns the table name of Departments.
+func (Departments) TableName() string {
+	return "departments"
+}
+
+// FacultyProjects maps the faculty_projects table (tabel_e5): Faculty Projects.
+type FacultyProjects struct {
+	// Projects ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Tipe Project
+	IDTipe uint `gorm:"column:id_tipe" json:"id_tipe"`
+	// Judul
+	Judul string `gorm:"column:judul" json:"judul"`
+	// Tanggal Mulai
+	TglMulai *time.Time `gorm:"column:tgl_mulai" json:"tgl_mulai"`
+	// Tanggal Selesai
+	TglSelesai *time.Time `gorm:"column:tgl_selesai" json:"tgl_selesai"`
+	// Abstrak
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+	// File Project
+	FileProject string `gorm:"column:file_project" json:"file_project"`
+	// Faculty ID
+	FacultyID uint `gorm:"column:faculty_id" json:"faculty_id"`
+}
+
+// TableName returns the table name of FacultyProjects.
+func (FacultyProjects) TableName() string {
+	return "faculty_projects"
+}
+
+// StudentsProjects maps the students_projects table (tabel_e6): Student Projects.
+type StudentsProjects struct {
+	// Projects ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Tipe Project
+	IDTipe uint `gorm:"column:id_tipe" json:"id_tipe"`
+	// Judul
+	Judul string `gorm:"column:judul" json:"judul"`
+	// Tanggal Mulai
+	TglMulai *time.Time `gorm:"column:tgl_mulai" json:"tgl_mulai"`
+	// Tanggal Selesai
+	TglSelesai *time.Time `gorm:"column:tgl_selesai" json:"tgl_selesai"`
+	// Abstrak
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+	// File Project
+	FileProject string `gorm:"column:file_project" json:"file_project"`
+	// Student ID
+	StudentID uint `gorm:"column:student_id" json:"student_id"`
+}
+
+// TableName returns the table name of StudentsProjects.
+func (StudentsProjects) TableName() string {
+	return "students_projects"
+}
+
+// ProjectType maps the project_type table (tabel_e7): Tipe Project.
+type ProjectType struct {
+	// ID Tipe
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Nama Tipe
+	Nama string `gorm:"column:nama" json:"nama"`
+	// Keterangan
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+}
+
+// TableName returns the table name of ProjectType.
+func (ProjectType) TableName() string {
+	return "project_type"
+}
+
+// Enrollments maps the enrollments table (tabel_e8): Enrollments.
+type Enrollments struct {
+	// Enrollment ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// Student ID
+	StudentID uint `gorm:"column:student_id" json:"student_id"`
+	// Course ID
+	CourseID uint `gorm:"column:course_id" json:"course_id"`
+	// Semester
+	Semester int `gorm:"column:semester" json:"semester"`
+	// Year
+	Year int `gorm:"column:year" json:"year"`
+}
+
+// TableName returns the table name of Enrollments.
+func (Enrollments) TableName() string {
+	return "enrollments"
+}
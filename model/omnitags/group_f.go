@@ -0,0 +1,111 @@
+// Code generated by omnitags gen models. DO NOT EDIT.
+
+package omnitags
+
+import "time"
+
+// History maps the history table (tabel_f1): History Pemesanan.
+type History struct {
+	// ID History
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// ID Pesanan
+	IDPesanan uint `gorm:"column:id_pesanan" json:"id_pesanan"`
+	// ID User
+	IDUser uint `gorm:"column:id_user" json:"id_user"`
+	// Nama Pemesan
+	Pemesan string `gorm:"column:pemesan" json:"pemesan"`
+	// Email
+	Email string `gorm:"column:email" json:"email"`
+	// No HP
+	HP string `gorm:"column:hp" json:"hp"`
+	// ID User
+	Tamu string `gorm:"column:tamu" json:"tamu"`
+	// Tipe
+	IDTipe uint `gorm:"column:id_tipe" json:"id_tipe"`
+	// Jumlah
+	Jlh int `gorm:"column:jlh" json:"jlh"`
+	// Harga Total
+	HargaTotal int `gorm:"column:harga_total" json:"harga_total"`
+	// Cek In
+	CekIn *time.Time `gorm:"column:cek_in" json:"cek_in"`
+	// Cek Out
+	CekOut *time.Time `gorm:"column:cek_out" json:"cek_out"`
+	// No Kamar
+	NoKamar string `gorm:"column:no_kamar" json:"no_kamar"`
+	// Tgl Perubahan
+	TglPerubahan *time.Time `gorm:"column:tgl_perubahan" json:"tgl_perubahan"`
+	// User Aktif
+	UserAktif string `gorm:"column:user_aktif" json:"user_aktif"`
+}
+
+// TableName returns the table name of History.
+func (History) TableName() string {
+	return "history"
+}
+
+// Approval maps the approval table (tabel_f2): Approval.
+type Approval struct {
+	// Approval ID
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+}
+
+// TableName returns the table name of Approval.
+func (Approval) TableName() string {
+	return "approval"
+}
+
+// TransaksiMetode holds the allowed values of transaksi.metode.
+type TransaksiMetode string
+
+const (
+	// Debit
+	TransaksiMetodeDebit TransaksiMetode = "debit"
+	// Kredit
+	TransaksiMetodeKredit TransaksiMetode = "kredit"
+)
+
+// Transaksi maps the transaksi table (tabel_f3): Transaksi.
+type Transaksi struct {
+	// ID Transaksi
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// ID User
+	IDUser uint `gorm:"column:id_user" json:"id_user"`
+	// Email
+	Email string `gorm:"column:email" json:"email"`
+	// ID Pesanan
+	IDPesanan uint `gorm:"column:id_pesanan" json:"id_pesanan"`
+	// Metode
+	Metode TransaksiMetode `gorm:"column:metode" json:"metode"`
+	// Jumlah Bayar
+	Bayar int `gorm:"column:bayar" json:"bayar"`
+	// Tanggal Transaksi
+	TglTransaksi *time.Time `gorm:"column:tgl_transaksi" json:"tgl_transaksi"`
+}
+
+// TableName returns the table name of Transaksi.
+func (Transaksi) TableName() string {
+	return "transaksi"
+}
+
+// Operations maps the operations table (tabel_f4): Operasional Hotel.
+type Operations struct {
+	// ID Operasional
+	ID uint `gorm:"column:id;primaryKey" json:"id"`
+	// No Kamar
+	NoKamar string `gorm:"column:no_kamar" json:"no_kamar"`
+	// ID User
+	IDUser uint `gorm:"column:id_user" json:"id_user"`
+	// ID Petugas
+	IDPetugas uint `gorm:"column:id_petugas" json:"id_petugas"`
+	// Status
+	Status string `gorm:"column:status" json:"status"`
+	// Keterangan
+	Keterangan string `gorm:"column:keterangan;type:text" json:"keterangan"`
+	// Tanggal Perubahan
+	TglPerubahan *time.Time `gorm:"column:tgl_perubahan" json:"tgl_perubahan"`
+}
+
+// TableName returns the table name of Operations.
+func (Operations) TableName() string {
+	return "operations"
+}
@@ -0,0 +1,30 @@
+// Package omnitagstest helps tests catch mistyped Omnitags keys.
+package omnitagstest
+
+import (
+	"testing"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+// CheckKeys fails t for every tabel_* key written in the Go code or templates below roots
+// that c does not define, e.g. CheckKeys(t, c, "../views", "."), and logs the keys of c nothing refers to.
+// Test files are not scanned. The report is returned, so a test can also fail on report.Unused.
+func CheckKeys(t testing.TB, c *config.Omnitags, roots ...string) *config.KeyReport {
+	t.Helper()
+	report, err := c.CheckReferences(config.ReferenceOptions{}, roots...)
+	if err != nil {
+		t.Fatalf("scanning omnitags keys: %v", err)
+	}
+	for _, ref := range report.Unknown {
+		if ref.Suggestion != "" {
+			t.Errorf("%s:%d: unknown omnitags key %s, did you mean %s?", ref.File, ref.Line, ref.Key, ref.Suggestion)
+		} else {
+			t.Errorf("%s:%d: unknown omnitags key %s", ref.File, ref.Line, ref.Key)
+		}
+	}
+	for _, key := range report.Unused {
+		t.Logf("unused omnitags key %s", key)
+	}
+	return report
+}
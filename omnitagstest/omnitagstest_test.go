@@ -0,0 +1,76 @@
+package omnitagstest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+// recorder collects what CheckKeys reports instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+	logs   []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Logf(format string, args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+func TestCheckKeysRepository(t *testing.T) {
+	c, err := config.LoadOmnitags(config.OmnitagsSource{Path: "../app.postman_environment.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := CheckKeys(t, c, "..")
+	if len(report.Unused) == 0 {
+		t.Error("no unused keys reported, the environment file has keys only the generic routes use")
+	}
+}
+
+func TestCheckKeysReportsUnknownAndUnused(t *testing.T) {
+	c := config.NewConfig()
+	c.LoadData(map[string]interface{}{"values": []interface{}{
+		map[string]interface{}{"key": "tabel_c2", "value": "users", "enabled": true},
+		map[string]interface{}{"key": "tabel_c2_field1", "value": "id", "enabled": true},
+		map[string]interface{}{"key": "tabel_c2_field2", "value": "email", "enabled": true},
+	}})
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"page.html":    "{{ alias \"tabel_c2\" }} {{ alias \"tabel_c2_feild1\" }}",
+		"main.go":      "package main\n\nvar key = \"tabel_c2_field1\"\n",
+		"main_test.go": "package main\n\nvar fixture = \"tabel_z9\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &recorder{}
+	report := CheckKeys(r, c, dir)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "unknown omnitags key tabel_c2_feild1, did you mean tabel_c2_field1?") {
+		t.Errorf("errors = %q, want one for tabel_c2_feild1", r.errors)
+	}
+	if len(r.logs) != 1 || !strings.Contains(r.logs[0], "unused omnitags key tabel_c2_field2") {
+		t.Errorf("logs = %q, want one for tabel_c2_field2", r.logs)
+	}
+	if len(report.Unused) != 1 || report.Unused[0] != "tabel_c2_field2" {
+		t.Errorf("report.Unused = %v, want [tabel_c2_field2]", report.Unused)
+	}
+}
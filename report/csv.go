@@ -0,0 +1,24 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSV writes the column headers followed by one record per row
+type CSV struct{}
+
+func (CSV) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (CSV) Extension() string { return ".csv" }
+
+func (CSV) Write(w io.Writer, r *Report) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(r.Columns); err != nil {
+		return err
+	}
+	if err := out.WriteAll(r.Rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
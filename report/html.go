@@ -0,0 +1,59 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+// HTML renders a standalone page styled for printing; Print opens the print dialog on load.
+// Template replaces the built-in page and receives the Report and Print as .Report and .Print.
+type HTML struct {
+	Print    bool
+	Template *template.Template
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Report.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; margin: 24px; }
+h1 { font-size: 18px; margin: 0 0 4px; }
+p { margin: 0 0 12px; color: #555; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #999; padding: 4px 6px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+tr { page-break-inside: avoid; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Report.Title}}</h1>
+<p>{{range .Report.Filters}}{{.}} &middot; {{end}}{{len .Report.Rows}} rows &middot; {{.Report.Generated.Format "2006-01-02 15:04"}}</p>
+<table>
+<thead><tr>{{range .Report.Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Report.Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- if .Print}}
+<script>window.onload = function () { window.print(); };</script>
+{{- end}}
+</body>
+</html>
+`))
+
+func (HTML) ContentType() string { return "text/html; charset=utf-8" }
+
+func (HTML) Extension() string { return ".html" }
+
+func (h HTML) Write(w io.Writer, r *Report) error {
+	tmpl := h.Template
+	if tmpl == nil {
+		tmpl = htmlTemplate
+	}
+	return tmpl.Execute(w, map[string]interface{}{"Report": r, "Print": h.Print})
+}
@@ -0,0 +1,207 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// PDF writes an A4 landscape table using the standard Helvetica fonts, so no font is embedded.
+// Long cells are cut to their column and the header row is repeated on every page.
+type PDF struct{}
+
+func (PDF) ContentType() string { return "application/pdf" }
+
+func (PDF) Extension() string { return ".pdf" }
+
+// Page geometry in points
+const (
+	pdfWidth     = 842.0
+	pdfHeight    = 595.0
+	pdfMargin    = 36.0
+	pdfRowHeight = 14.0
+	pdfFontSize  = 9.0
+	pdfTitleSize = 14.0
+	pdfPadding   = 4.0
+	pdfMaxColumn = 220.0
+)
+
+// helveticaWidths are the advance widths of ASCII 32-126 in Helvetica, per 1000 units of font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth measures s in points; bold text is about five percent wider
+func textWidth(s string, size float64, bold bool) float64 {
+	var units int
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if bold {
+		width *= 1.05
+	}
+	return width
+}
+
+// fitText cuts s with an ellipsis until it is at most width points wide
+func fitText(s string, width, size float64, bold bool) string {
+	if textWidth(s, size, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// pdfString encodes s as a literal string in WinAnsiEncoding; other characters become '?'
+func pdfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 32:
+			b.WriteByte(' ')
+		case r <= 126 || (r >= 160 && r <= 255):
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// columnWidths sizes the columns after their content and stretches or shrinks them to the page
+func columnWidths(r *Report, available float64) []float64 {
+	widths := make([]float64, len(r.Columns))
+	var total float64
+	for i, column := range r.Columns {
+		widths[i] = textWidth(column, pdfFontSize, true)
+		for _, row := range r.Rows {
+			if i < len(row) {
+				widths[i] = math.Max(widths[i], textWidth(row[i], pdfFontSize, false))
+			}
+		}
+		widths[i] = math.Min(widths[i]+2*pdfPadding, pdfMaxColumn)
+		total += widths[i]
+	}
+	for i := range widths {
+		widths[i] *= available / total
+	}
+	return widths
+}
+
+func (PDF) Write(w io.Writer, r *Report) error {
+	available := pdfWidth - 2*pdfMargin
+	var widths []float64
+	if len(r.Columns) > 0 {
+		widths = columnWidths(r, available)
+	}
+
+	// The table starts below the title and the line of filters
+	tableTop := pdfHeight - pdfMargin - pdfTitleSize - 2*pdfRowHeight
+	perPage := int((tableTop - pdfRowHeight - pdfMargin) / pdfRowHeight)
+	pageCount := (len(r.Rows) + perPage - 1) / perPage
+	if pageCount == 0 {
+		pageCount = 1
+	}
+
+	subtitle := strings.Join(append(append([]string{}, r.Filters...), fmt.Sprintf("%d rows", len(r.Rows)), "generated "+r.Generated.Format("2006-01-02 15:04")), " - ")
+	var contents []string
+	for page := 0; page < pageCount; page++ {
+		var s strings.Builder
+		text := func(font string, size, x, y float64, value string) {
+			fmt.Fprintf(&s, "BT /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", font, size, x, y, pdfString(value))
+		}
+		line := func(y float64) {
+			fmt.Fprintf(&s, "%.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfMargin+available, y)
+		}
+
+		y := pdfHeight - pdfMargin - pdfTitleSize
+		text("F2", pdfTitleSize, pdfMargin, y, fitText(r.Title, available, pdfTitleSize, true))
+		text("F1", pdfFontSize, pdfMargin, y-pdfRowHeight, fitText(subtitle, available, pdfFontSize, false))
+
+		// Header row on a grey band
+		y = tableTop
+		fmt.Fprintf(&s, "0.9 g %.2f %.2f %.2f %.2f re f 0 g\n", pdfMargin, y-pdfRowHeight, available, pdfRowHeight)
+		x := pdfMargin
+		for i, column := range r.Columns {
+			text("F2", pdfFontSize, x+pdfPadding, y-pdfRowHeight+pdfPadding, fitText(column, widths[i]-2*pdfPadding, pdfFontSize, true))
+			x += widths[i]
+		}
+		s.WriteString("0.5 w 0.6 G\n")
+		line(y)
+		y -= pdfRowHeight
+		line(y)
+
+		end := (page + 1) * perPage
+		if end > len(r.Rows) {
+			end = len(r.Rows)
+		}
+		for _, row := range r.Rows[page*perPage : end] {
+			x = pdfMargin
+			for i := range r.Columns {
+				if i < len(row) {
+					text("F1", pdfFontSize, x+pdfPadding, y-pdfRowHeight+pdfPadding, fitText(row[i], widths[i]-2*pdfPadding, pdfFontSize, false))
+				}
+				x += widths[i]
+			}
+			y -= pdfRowHeight
+			line(y)
+		}
+
+		footer := fmt.Sprintf("Page %d of %d", page+1, pageCount)
+		text("F1", pdfFontSize, pdfWidth-pdfMargin-textWidth(footer, pdfFontSize, false), pdfMargin/2, footer)
+		contents = append(contents, s.String())
+	}
+
+	// Objects 1-5 are the catalog, page tree, fonts and info; each page adds a page and a content stream
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, pageCount)
+	for i := range kids {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (omnitags) /CreationDate (D:%s) >>", pdfString(r.Title), r.Generated.Format("20060102150405")))
+	for i, content := range contents {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfWidth, pdfHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,146 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pdfObjects checks the cross-reference table of a PDF and returns the body of every object by number
+func pdfObjects(t *testing.T, data []byte) map[int]string {
+	t.Helper()
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if m == nil {
+		t.Fatalf("no startxref at the end of\n%s", data)
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	lines := strings.Split(string(data[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil || first != 0 {
+		t.Fatalf("xref subsection %q", lines[1])
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Errorf("xref entry 0 = %q, want the free list head", lines[2])
+	}
+	if want := fmt.Sprintf("/Size %d ", count); !strings.Contains(string(data[xref:]), want) {
+		t.Errorf("trailer does not contain %q", want)
+	}
+
+	objects := make(map[int]string)
+	for n := 1; n < count; n++ {
+		entry := lines[2+n]
+		if len(entry) != 19 || !strings.HasSuffix(entry, " 00000 n ") {
+			t.Fatalf("xref entry %d = %q, want a 20 byte in-use entry", n, entry)
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		header := fmt.Sprintf("%d 0 obj\n", n)
+		if !bytes.HasPrefix(data[offset:], []byte(header)) {
+			t.Fatalf("xref entry %d points at %q, want %q", n, data[offset:offset+len(header)], header)
+		}
+		body := string(data[offset+len(header):])
+		objects[n] = body[:strings.Index(body, "\nendobj\n")]
+	}
+	return objects
+}
+
+// pdfStream returns the content of a stream object after checking its /Length
+func pdfStream(t *testing.T, object string) string {
+	t.Helper()
+	var length int
+	if _, err := fmt.Sscanf(object, "<< /Length %d >>", &length); err != nil {
+		t.Fatalf("stream without length: %q", object)
+	}
+	start := strings.Index(object, "stream\n") + len("stream\n")
+	if !strings.HasPrefix(object[start+length:], "endstream") {
+		t.Errorf("/Length %d does not end at endstream", length)
+	}
+	return object[start : start+length]
+}
+
+func TestPDF(t *testing.T) {
+	r := &Report{
+		Title:     "Transaksi (2024)",
+		Filters:   []string{"metode = cash"},
+		Columns:   []string{"id", "nama"},
+		Generated: time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+	}
+	for i := 1; i <= 70; i++ {
+		r.Rows = append(r.Rows, []string{strconv.Itoa(i), fmt.Sprintf("row %d", i)})
+	}
+
+	var b bytes.Buffer
+	if err := (PDF{}).Write(&b, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b.Bytes(), []byte("%PDF-1.4\n")) {
+		t.Errorf("missing PDF header")
+	}
+	objects := pdfObjects(t, b.Bytes())
+
+	// 33 rows fit on a page
+	if !strings.Contains(objects[2], "/Kids [6 0 R 8 0 R 10 0 R] /Count 3") {
+		t.Errorf("page tree = %q, want 3 pages", objects[2])
+	}
+	if !strings.Contains(objects[5], `/Title (Transaksi \(2024\))`) {
+		t.Errorf("info = %q, want the escaped title", objects[5])
+	}
+	for page, first := range []int{1, 34, 67} {
+		if want := fmt.Sprintf("/Contents %d 0 R", 7+2*page); !strings.Contains(objects[6+2*page], want) {
+			t.Errorf("page %d = %q, want %s", page+1, objects[6+2*page], want)
+		}
+		content := pdfStream(t, objects[7+2*page])
+		for _, want := range []string{
+			`Tf 36.00 545.00 Td (Transaksi \(2024\)) Tj`,
+			"(metode = cash - 70 rows - generated 2024-03-31 12:00) Tj",
+			"/F2 9.0 Tf", "(id) Tj", "(nama) Tj",
+			fmt.Sprintf("(row %d) Tj", first),
+			fmt.Sprintf("(Page %d of 3) Tj", page+1),
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("page %d does not contain %q\n%s", page+1, want, content)
+			}
+		}
+		if page > 0 && strings.Contains(content, fmt.Sprintf("(row %d) Tj", first-1)) {
+			t.Errorf("page %d repeats row %d of the previous page", page+1, first-1)
+		}
+	}
+	if strings.Count(pdfStream(t, objects[11]), "(row ") != 4 {
+		t.Errorf("the last page does not hold the 4 remaining rows")
+	}
+}
+
+func TestPDFEmpty(t *testing.T) {
+	var b bytes.Buffer
+	if err := (PDF{}).Write(&b, &Report{Title: "Kosong"}); err != nil {
+		t.Fatal(err)
+	}
+	objects := pdfObjects(t, b.Bytes())
+	if len(objects) != 7 || !strings.Contains(objects[2], "/Count 1") {
+		t.Errorf("empty report has %d objects, want one page", len(objects))
+	}
+	if content := pdfStream(t, objects[7]); !strings.Contains(content, "(Page 1 of 1) Tj") {
+		t.Errorf("empty page = %q", content)
+	}
+}
+
+func TestFitText(t *testing.T) {
+	long := strings.Repeat("lebar ", 40)
+	got := fitText(long, 100, pdfFontSize, false)
+	if !strings.HasSuffix(got, "...") || textWidth(got, pdfFontSize, false) > 100 {
+		t.Errorf("fitText = %q, want at most 100 points with an ellipsis", got)
+	}
+	if got := fitText("id", 100, pdfFontSize, false); got != "id" {
+		t.Errorf("fitText of a short text = %q", got)
+	}
+	if got := pdfString("a\\bé中\n"); got != "(a\\\\b\xe9? )" {
+		t.Errorf("pdfString = %q", got)
+	}
+}
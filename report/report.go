@@ -0,0 +1,59 @@
+// Package report renders tabular reports as printable HTML, CSV and PDF.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report is a titled table of rows, already formatted as text
+type Report struct {
+	Title string
+	// Filters describes the conditions the rows were selected with, e.g. "min_bayar = 10000"
+	Filters   []string
+	Columns   []string
+	Rows      [][]string
+	Generated time.Time
+}
+
+// Writer renders a report in one format
+type Writer interface {
+	ContentType() string
+	Extension() string
+	Write(w io.Writer, r *Report) error
+}
+
+// writers lists the built-in formats by name
+var writers = map[string]Writer{
+	"html":  HTML{},
+	"print": HTML{Print: true},
+	"csv":   CSV{},
+	"pdf":   PDF{},
+}
+
+// NewWriter returns the writer of format
+func NewWriter(format string) (Writer, error) {
+	w, ok := writers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown report format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+	}
+	return w, nil
+}
+
+// Formats returns the names of the built-in formats
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for name := range writers {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// FileName returns a download name such as transaksi_report_20240331.pdf
+func FileName(name string, w Writer, at time.Time) string {
+	return fmt.Sprintf("%s_report_%s%s", name, at.Format("20060102"), w.Extension())
+}
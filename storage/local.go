@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Local stores files on the local disk below Root
+type Local struct {
+	Root string
+}
+
+// NewLocal returns a disk storage rooted at root, the working directory when empty
+func NewLocal(root string) *Local {
+	if root == "" {
+		root = "."
+	}
+	return &Local{Root: root}
+}
+
+// path joins dir and name below the root and rejects anything that escapes it
+func (l *Local) path(dir, name string) (string, error) {
+	root, err := filepath.Abs(l.Root)
+	if err != nil {
+		return "", err
+	}
+	if name != "" && (name != filepath.Base(name) || strings.HasPrefix(name, ".")) {
+		return "", fmt.Errorf("%w %q", ErrInvalidName, name)
+	}
+	path := filepath.Join(root, filepath.FromSlash(dir), name)
+	if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q is outside the storage root", ErrInvalidName, filepath.Join(dir, name))
+	}
+	return path, nil
+}
+
+// Save writes r to dir/name, creating dir as needed; a partial file is removed on error
+func (l *Local) Save(dir, name string, r io.Reader) (Object, error) {
+	path, err := l.path(dir, name)
+	if err != nil {
+		return Object{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Object{}, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return Object{}, err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		os.Remove(path)
+		return Object{}, err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return Object{}, err
+	}
+	return l.stat(path)
+}
+
+// Open returns the content of dir/name
+func (l *Local) Open(dir, name string) (io.ReadCloser, Object, error) {
+	path, err := l.path(dir, name)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	obj, err := l.stat(path)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, Object{}, notFound(err)
+	}
+	return file, obj, nil
+}
+
+// Delete removes dir/name
+func (l *Local) Delete(dir, name string) error {
+	path, err := l.path(dir, name)
+	if err != nil {
+		return err
+	}
+	return notFound(os.Remove(path))
+}
+
+// List returns the files of dir sorted by name; a missing dir holds no files
+func (l *Local) List(dir string) ([]Object, error) {
+	path, err := l.path(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Object{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []Object{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, Object{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+func (l *Local) stat(path string) (Object, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Object{}, notFound(err)
+	}
+	if !info.Mode().IsRegular() {
+		return Object{}, ErrNotFound
+	}
+	return Object{Name: info.Name(), Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// notFound maps missing files to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	root := t.TempDir()
+	l := NewLocal(root)
+
+	obj, err := l.Save("assets/img/tabel_b1/3", "foto.png", strings.NewReader("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Name != "foto.png" || obj.Size != 3 {
+		t.Errorf("Save = %+v, want foto.png of 3 bytes", obj)
+	}
+	if _, err := os.Stat(filepath.Join(root, "assets", "img", "tabel_b1", "3", "foto.png")); err != nil {
+		t.Errorf("the file is not below the root: %v", err)
+	}
+	if _, err := l.Save("assets/img/tabel_b1/3", "foto.png", strings.NewReader("other")); err == nil {
+		t.Error("Save overwrites an existing file")
+	}
+
+	r, obj, err := l.Open("./assets/img/tabel_b1/3", "foto.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(r)
+	r.Close()
+	if string(content) != "png" || obj.Size != 3 {
+		t.Errorf("Open = %q, %+v, want the saved content", content, obj)
+	}
+
+	if objects, err := l.List("assets/img/tabel_b1/3"); err != nil || len(objects) != 1 || objects[0].Name != "foto.png" {
+		t.Errorf("List = %+v, %v, want foto.png", objects, err)
+	}
+	if objects, err := l.List("assets/img/tabel_b1/4"); err != nil || len(objects) != 0 {
+		t.Errorf("List of a missing directory = %+v, %v, want none", objects, err)
+	}
+	// Directories are not files
+	if _, _, err := l.Open("assets/img", "tabel_b1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of a directory = %v, want ErrNotFound", err)
+	}
+
+	if err := l.Delete("assets/img/tabel_b1/3", "foto.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Delete("assets/img/tabel_b1/3", "foto.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	if _, _, err := l.Open("assets/img/tabel_b1/3", "foto.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsPathTraversal(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "uploads")
+	secret := filepath.Join(parent, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLocal(root)
+
+	tests := []struct {
+		dir, name string
+	}{
+		{"img", "../../secret.txt"},
+		{"img", ".."},
+		{"img", "../secret.txt"},
+		{"img", "sub/foto.png"},
+		{"img", ".htaccess"},
+		{"..", "secret.txt"},
+		{"img/../..", "secret.txt"},
+		{"../uploads2", "foto.png"},
+	}
+	for _, tt := range tests {
+		if _, err := l.Save(tt.dir, tt.name, strings.NewReader("x")); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Save(%q, %q) = %v, want ErrInvalidName", tt.dir, tt.name, err)
+		}
+		if _, _, err := l.Open(tt.dir, tt.name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Open(%q, %q) = %v, want ErrInvalidName", tt.dir, tt.name, err)
+		}
+		if err := l.Delete(tt.dir, tt.name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Delete(%q, %q) = %v, want ErrInvalidName", tt.dir, tt.name, err)
+		}
+	}
+	if _, err := l.List("../"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("List(../) = %v, want ErrInvalidName", err)
+	}
+
+	if content, err := os.ReadFile(secret); err != nil || string(content) != "secret" {
+		t.Errorf("the file outside the root changed: %q, %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "uploads2")); !os.IsNotExist(err) {
+		t.Errorf("a directory was created outside the root: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+// Package storage keeps uploaded files behind a backend-agnostic interface.
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when a file does not exist
+var ErrNotFound = errors.New("file not found")
+
+// ErrInvalidName is returned for names and directories that would leave the storage root
+var ErrInvalidName = errors.New("invalid file name")
+
+// Object describes a stored file
+type Object struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Storage saves, serves and deletes files grouped in directories, e.g. ./assets/img/tabel_b1/3
+type Storage interface {
+	Save(dir, name string, r io.Reader) (Object, error)
+	Open(dir, name string) (io.ReadCloser, Object, error)
+	Delete(dir, name string) error
+	List(dir string) ([]Object, error)
+}
@@ -16,6 +16,8 @@ type APIResponse struct {
 type APIErrorParams struct {
 	Msg string
 	Err error
+	// Data carries error details, e.g. field errors; an empty object is sent when nil
+	Data interface{}
 }
 
 type APISuccessParams struct {
@@ -52,6 +54,9 @@ func CallUserError(c *gin.Context, params APIErrorParams) {
 		Msg:     params.Msg,
 		Data:    map[string]interface{}{},
 	}
+	if params.Data != nil {
+		response.Data = params.Data
+	}
 	c.JSON(http.StatusBadRequest, response)
 }
 
@@ -97,3 +102,14 @@ func CallUserNotAuthorized(c *gin.Context, params APIErrorParams) {
 	}
 	c.JSON(http.StatusUnauthorized, response)
 }
+
+// CallForbidden is for return API response with status code 403 when the user lacks the required role
+func CallForbidden(c *gin.Context, params APIErrorParams) {
+	response := APIResponse{
+		Success: false,
+		Error:   params.Err.Error(),
+		Msg:     params.Msg,
+		Data:    map[string]interface{}{},
+	}
+	c.JSON(http.StatusForbidden, response)
+}
@@ -0,0 +1,138 @@
+// Package view renders html/template pages named after the Omnitags view paths, e.g. contents/tabel_c2/index.
+package view
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+// ErrNotFound is returned when a view has no template file
+var ErrNotFound = errors.New("view not found")
+
+// ContextKey stores the renderer of a request in the gin context
+const ContextKey = "view"
+
+// Renderer loads templates from Root; a view path p is the file <Root>/<p>.html.
+// Files in <Root>/layouts are parsed with every view, so views can share a header and footer.
+type Renderer struct {
+	Root string
+	// Reload parses templates on every request instead of caching them, for development
+	Reload bool
+
+	mu    sync.Mutex
+	cache map[string]*template.Template
+}
+
+// NewRenderer returns a renderer for the templates below root, "views" when empty
+func NewRenderer(root string) *Renderer {
+	if root == "" {
+		root = "views"
+	}
+	return &Renderer{Root: root, cache: make(map[string]*template.Template)}
+}
+
+// FromContext returns the renderer installed by the ViewRenderer middleware, or nil
+func FromContext(c *gin.Context) *Renderer {
+	if r, ok := c.Get(ContextKey); ok {
+		return r.(*Renderer)
+	}
+	return nil
+}
+
+// Funcs returns the template functions backed by omnitags:
+// alias "tabel_c2_field6" is the alias of a key or its value when it has none, title "tabel_c2" 2 is Titles["tabel_c2_v2"],
+// input "tabel_c2_field6" ["filter1"] is the form input name and flash "tabel_c2" is the name of the flash variable.
+// Unknown keys render as "" unless config strict mode is on, then they fail the template.
+func Funcs(omnitags *config.Omnitags) template.FuncMap {
+	lookup := func(value string, err error) (string, error) {
+		if err != nil && !config.Strict() {
+			return "", nil
+		}
+		return value, err
+	}
+	return template.FuncMap{
+		"alias": func(key string) (string, error) {
+			if alias, ok := omnitags.Aliases[key+"_alias"]; ok {
+				return alias, nil
+			}
+			return lookup(omnitags.Lookup(key))
+		},
+		"title": func(key string, n int) (string, error) {
+			return lookup(omnitags.LookupIn("titles", fmt.Sprintf("%s_v%d", key, n)))
+		},
+		"input": func(key string, kind ...string) (string, error) {
+			suffix := "input"
+			if len(kind) > 0 {
+				suffix = kind[0]
+			}
+			return lookup(omnitags.LookupIn("vinput", key+"_"+suffix))
+		},
+		"flash": func(key string) (string, error) {
+			return lookup(omnitags.LookupIn("flash", key))
+		},
+	}
+}
+
+// Template returns the template of the view path bound to the functions of omnitags
+func (r *Renderer) Template(path string, omnitags *config.Omnitags) (*template.Template, error) {
+	tmpl, err := r.load(path)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Funcs(Funcs(omnitags)), nil
+}
+
+// load parses a view with the layouts, from the cache unless Reload is set
+func (r *Renderer) load(path string) (*template.Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tmpl, ok := r.cache[path]; ok && !r.Reload {
+		return tmpl, nil
+	}
+
+	file := filepath.Join(r.Root, filepath.FromSlash(path)+".html")
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, file)
+	}
+	layouts, err := filepath.Glob(filepath.Join(r.Root, "layouts", "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse against empty maps, the functions of the current snapshot are bound in Template
+	tmpl, err := template.New(filepath.Base(file)).Funcs(Funcs(&config.Omnitags{})).ParseFiles(append([]string{file}, layouts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if r.cache == nil {
+		r.cache = make(map[string]*template.Template)
+	}
+	r.cache[path] = tmpl
+	return tmpl, nil
+}
+
+// HTML renders tmpl with data; nothing is written when the template fails
+func HTML(c *gin.Context, status int, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	// Set explicitly, the CORS middleware already put application/json in the header
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+	return nil
+}
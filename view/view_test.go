@@ -0,0 +1,160 @@
+package view
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khenjyjohnelson/golang-omnitags/config"
+)
+
+// viewTestEnvironment describes one table with an alias
+const viewTestEnvironment = `{
+	"name": "test",
+	"values": [
+		{"key": "tabel_c2", "value": "users", "enabled": true},
+		{"key": "tabel_c2_alias", "value": "Pengguna", "enabled": true},
+		{"key": "tabel_c2_field1", "value": "id", "enabled": true},
+		{"key": "tabel_c2_field2", "value": "email", "enabled": true}
+	]
+}`
+
+func loadViewTestOmnitags(t *testing.T) *config.Omnitags {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.postman_environment.json")
+	if err := os.WriteFile(path, []byte(viewTestEnvironment), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := config.NewOmnitagsStore(config.OmnitagsSource{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store.Get()
+}
+
+// writeView writes the template of a view path below root
+func writeView(t *testing.T, root, path, content string) {
+	t.Helper()
+	file := filepath.Join(root, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func execute(t *testing.T, tmpl *template.Template, data interface{}) string {
+	t.Helper()
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestFuncs(t *testing.T) {
+	omnitags := loadViewTestOmnitags(t)
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{alias "tabel_c2"}}`, "Pengguna"},
+		{`{{alias "tabel_c2_field2"}}`, "email"},
+		{`{{title "tabel_c2" 2}}`, "List of users"},
+		{`{{input "tabel_c2_field2"}}`, "txt_email"},
+		{`{{input "tabel_c2_field2" "filter1"}}`, "min_email"},
+		{`{{flash "tabel_c2"}}`, "pesan_users"},
+		{`{{alias "tabel_z9"}}`, ""},
+		{`{{title "tabel_c2" 9}}`, ""},
+	}
+	for _, tt := range tests {
+		tmpl := template.Must(template.New("").Funcs(Funcs(omnitags)).Parse(tt.text))
+		if got := execute(t, tmpl, nil); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFuncsStrict(t *testing.T) {
+	config.SetStrict(true)
+	t.Cleanup(func() { config.SetStrict(false) })
+
+	tmpl := template.Must(template.New("").Funcs(Funcs(loadViewTestOmnitags(t))).Parse(`{{alias "tabel_z9"}}`))
+	err := tmpl.Execute(&strings.Builder{}, nil)
+	if !errors.Is(err, config.ErrUnknownKey) {
+		t.Errorf("Execute = %v, want an unknown key error", err)
+	}
+}
+
+func TestRendererTemplate(t *testing.T) {
+	root := t.TempDir()
+	writeView(t, root, "layouts/header.html", `{{define "header"}}<h1>{{alias "tabel_c2"}}</h1>{{end}}`)
+	writeView(t, root, "contents/tabel_c2/index.html", `{{template "header" .}}{{.}}`)
+	omnitags := loadViewTestOmnitags(t)
+
+	r := NewRenderer(root)
+	tmpl, err := r.Template("contents/tabel_c2/index", omnitags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := execute(t, tmpl, "rows"); got != "<h1>Pengguna</h1>rows" {
+		t.Errorf("rendered %q, want the layout header and the data", got)
+	}
+
+	if _, err := r.Template("contents/tabel_c2/daftar", omnitags); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Template of a missing view = %v, want ErrNotFound", err)
+	}
+
+	// Cached until Reload is set
+	writeView(t, root, "contents/tabel_c2/index.html", `changed`)
+	if tmpl, err = r.Template("contents/tabel_c2/index", omnitags); err != nil {
+		t.Fatal(err)
+	}
+	if got := execute(t, tmpl, "rows"); got != "<h1>Pengguna</h1>rows" {
+		t.Errorf("rendered %q, want the cached template", got)
+	}
+	r.Reload = true
+	if tmpl, err = r.Template("contents/tabel_c2/index", omnitags); err != nil {
+		t.Fatal(err)
+	}
+	if got := execute(t, tmpl, "rows"); got != "changed" {
+		t.Errorf("rendered %q, want the changed file with Reload", got)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		text string
+		err  bool
+		body string
+	}{
+		{"renders", `<p>{{.}}</p>`, false, "<p>&lt;b&gt;</p>"},
+		{"failing template writes nothing", `<p>{{.}}</p>{{template "missing"}}`, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Header("Content-Type", "application/json")
+			err := HTML(c, http.StatusOK, template.Must(template.New("").Parse(tt.text)), "<b>")
+			if (err != nil) != tt.err {
+				t.Fatalf("HTML = %v, want error %v", err, tt.err)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+			if !tt.err && w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q, want text/html", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}